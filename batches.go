@@ -0,0 +1,106 @@
+package ringbuffer
+
+import "time"
+
+// Batches returns a channel delivering the buffer items in batches of up to n,
+// as they become available, for a `for batch := range rb.Batches(n, stop)` consumer loop.
+// Behavior:
+// - A background goroutine waits for at least one item, then takes up to n items
+// at once and sends them as a new slice
+// - Waiting ignores the read timeout, the loop lasts until the buffer closes or stop fires
+// - The channel is closed once the buffer is closed and drained, or once stop fires;
+// after CloseGraceful every queued item is delivered first
+// - A batch already taken from the buffer is always delivered, so receive until
+// the channel is closed to lose no items and leak no goroutine
+// - In non-blocking mode only the items already queued are delivered
+// - Returns a closed channel if n <= 0
+// - A nil stop never fires
+// - The goroutines are tracked by Wait
+func (r *RingBuffer[T]) Batches(n int, stop <-chan struct{}) <-chan []T {
+	ch := make(chan []T)
+	if r == nil || n <= 0 {
+		close(ch)
+		return ch
+	}
+
+	done := make(chan struct{})
+
+	// Wake the waiting loop so it notices stop
+	r.goroutines.Add(2)
+	go func() {
+		defer r.goroutines.Done()
+
+		select {
+		case <-stop:
+			r.mu.Lock()
+			if r.writeCond != nil {
+				r.writeCond.Broadcast()
+			}
+			r.mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	go func() {
+		defer r.goroutines.Done()
+		defer close(ch)
+		defer close(done)
+
+		for {
+			batch, ok := r.nextBatch(n, stop)
+			if !ok {
+				return
+			}
+			ch <- batch
+		}
+	}()
+
+	return ch
+}
+
+// nextBatch waits for items and removes up to n of them for Batches.
+// Returns ok false once the buffer is closed and drained, stop fired,
+// or the buffer is empty and not blocking.
+func (r *RingBuffer[T]) nextBatch(n int, stop <-chan struct{}) (batch []T, ok bool) {
+	r.mu.Lock()
+	defer func() {
+		if ok && r.block && r.blockedWriters > 0 {
+			r.readCond.Signal()
+		}
+		r.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stop:
+			return nil, false
+		default:
+		}
+
+		if err := r.readErr(true, "Batches"); err != nil {
+			return nil, false
+		}
+
+		if r.w != r.r || r.isFull {
+			break
+		}
+
+		if !r.block {
+			return nil, false
+		}
+
+		if err := r.waitWriteUntil(time.Time{}, "Batches"); err != nil {
+			return nil, false
+		}
+	}
+
+	n = min(n, r.Length(true))
+	batch = make([]T, n)
+	r.copyOut(batch)
+
+	r.consume(n)
+
+	r.afterRead(n)
+
+	return batch, true
+}
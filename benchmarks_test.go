@@ -2,6 +2,9 @@ package ringbuffer
 
 import (
 	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -189,3 +192,271 @@ func BenchmarkBlockingWriteGet(b *testing.B) {
 		})
 	}
 }
+
+// BenchmarkOverwriteWriteMany tests bulk overwrite of batches larger than the free space
+func BenchmarkOverwriteWriteMany(b *testing.B) {
+	sizes := []int{64, 1024, 8192}
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("Size_%d", size), func(b *testing.B) {
+			rb := New[int](size).WithOverwrite(true)
+			batch := make([]int, size+size/2)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rb.WriteMany(batch)
+			}
+		})
+	}
+}
+
+// BenchmarkFlush compares zeroing Flush against position-only FlushFast
+func BenchmarkFlush(b *testing.B) {
+	const size = 1 << 20
+	rb := New[int](size)
+
+	b.Run("Flush", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rb.Write(i)
+			rb.Flush()
+		}
+	})
+
+	b.Run("FlushFast", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rb.Write(i)
+			rb.FlushFast()
+		}
+	})
+}
+
+// unpaddedSPSC is SPSC without cache line padding, used as a baseline for BenchmarkSPSC.
+type unpaddedSPSC[T any] struct {
+	head atomic.Uint64
+	tail atomic.Uint64
+	buf  []T
+	mask uint64
+}
+
+func (s *unpaddedSPSC[T]) TryWrite(item T) bool {
+	tail := s.tail.Load()
+	if tail-s.head.Load() == uint64(len(s.buf)) {
+		return false
+	}
+	s.buf[tail&s.mask] = item
+	s.tail.Store(tail + 1)
+	return true
+}
+
+func (s *unpaddedSPSC[T]) TryRead() (item T, ok bool) {
+	head := s.head.Load()
+	if head == s.tail.Load() {
+		return item, false
+	}
+	item = s.buf[head&s.mask]
+	s.head.Store(head + 1)
+	return item, true
+}
+
+// BenchmarkSPSC measures producer/consumer throughput with and without cache line padding.
+// Run with GOMAXPROCS >= 2 so the producer and consumer land on different cores.
+func BenchmarkSPSC(b *testing.B) {
+	const size = 1024
+
+	b.Run("Padded", func(b *testing.B) {
+		s := NewSPSC[int](size)
+		done := make(chan struct{})
+		go func() {
+			for i := 0; i < b.N; {
+				if _, err := s.TryRead(); err != nil {
+					runtime.Gosched()
+					continue
+				}
+				i++
+			}
+			close(done)
+		}()
+
+		for i := 0; i < b.N; {
+			if s.TryWrite(i) != nil {
+				runtime.Gosched()
+				continue
+			}
+			i++
+		}
+		<-done
+	})
+
+	b.Run("Unpadded", func(b *testing.B) {
+		s := &unpaddedSPSC[int]{buf: make([]int, size), mask: size - 1}
+		done := make(chan struct{})
+		go func() {
+			for i := 0; i < b.N; {
+				if _, ok := s.TryRead(); !ok {
+					runtime.Gosched()
+					continue
+				}
+				i++
+			}
+			close(done)
+		}()
+
+		for i := 0; i < b.N; {
+			if !s.TryWrite(i) {
+				runtime.Gosched()
+				continue
+			}
+			i++
+		}
+		<-done
+	})
+}
+
+// BenchmarkGetOne measures the non-blocking GetOne hot path
+func BenchmarkGetOne(b *testing.B) {
+	rb := New[int](1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rb.Write(i)
+		rb.GetOne()
+	}
+}
+
+// BenchmarkTimedWait ping-pongs items between two buffers with a timeout, so every
+// read and write waits with a deadline: the waits share one timer per condition
+// instead of allocating their own.
+func BenchmarkTimedWait(b *testing.B) {
+	ping := New[int](1).WithTimeout(time.Hour)
+	pong := New[int](1).WithTimeout(time.Hour)
+	go func() {
+		for {
+			item, err := ping.GetOne()
+			if err != nil {
+				return
+			}
+			pong.Write(item)
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ping.Write(i)
+		pong.GetOne()
+	}
+	b.StopTimer()
+	ping.Close()
+}
+
+// BenchmarkBlockedReaders keeps 100 readers blocked with a timeout on an empty buffer
+// and feeds them items, so the readers keep going back to waiting with a deadline:
+// the waits share the buffer's timer, they allocate neither a goroutine nor a channel.
+func BenchmarkBlockedReaders(b *testing.B) {
+	const readers = 100
+	rb := New[int](readers).WithTimeout(time.Hour)
+
+	var wg sync.WaitGroup
+	var reads atomic.Int64
+	for range readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := rb.GetOne(); err != nil {
+					return
+				}
+				reads.Add(1)
+			}
+		}()
+	}
+	for rb.Stats().BlockedReads < readers {
+		runtime.Gosched()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rb.Write(i)
+	}
+	for reads.Load() < int64(b.N) {
+		runtime.Gosched()
+	}
+	b.StopTimer()
+
+	rb.Close()
+	wg.Wait()
+}
+
+// BenchmarkConcurrentPeek measures parallel PeekOne with the exclusive lock and with WithRWMutex.
+// Run with GOMAXPROCS >= 2, on a single core the read lock can't let peeks overlap.
+func BenchmarkConcurrentPeek(b *testing.B) {
+	for _, shared := range []bool{false, true} {
+		name := "Mutex"
+		if shared {
+			name = "RWMutex"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			rb := New[int](1024).WithRWMutex(shared)
+			for i := range 512 {
+				rb.Write(i)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					rb.PeekOne()
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkMPMC measures throughput with an equal number of producers and consumers,
+// each moving b.N items in total, for the mutex-based RingBuffer and the lock-free MPMC.
+// Run with GOMAXPROCS >= 8 to see contention, on a single core goroutines never overlap.
+func BenchmarkMPMC(b *testing.B) {
+	const size = 1024
+
+	run := func(b *testing.B, pairs int, write func(int) error, read func() error) {
+		done := make(chan struct{})
+		for p := range pairs {
+			n := b.N / pairs
+			if p == 0 {
+				n += b.N % pairs
+			}
+
+			go func() {
+				for i := range n {
+					write(i)
+				}
+			}()
+			go func() {
+				for range n {
+					read()
+				}
+				done <- struct{}{}
+			}()
+		}
+
+		for range pairs {
+			<-done
+		}
+	}
+
+	for _, pairs := range []int{1, 8, 64} {
+		b.Run(fmt.Sprintf("Mutex/Pairs_%d", pairs), func(b *testing.B) {
+			rb := New[int](size).WithBlocking(true)
+			run(b, pairs, rb.Write, func() error {
+				_, err := rb.GetOne()
+				return err
+			})
+		})
+
+		b.Run(fmt.Sprintf("MPMC/Pairs_%d", pairs), func(b *testing.B) {
+			m := NewMPMC[int](size).WithBlocking(true)
+			run(b, pairs, m.Write, func() error {
+				_, err := m.Read()
+				return err
+			})
+		})
+	}
+}
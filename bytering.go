@@ -0,0 +1,90 @@
+package ringbuffer
+
+import (
+	stderrors "errors"
+	"io"
+
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+)
+
+var _ io.ReadWriteCloser = (*ByteRing)(nil)
+
+// ByteRing is a bounded in-memory pipe of bytes built on a RingBuffer[byte],
+// usable wherever an io.ReadWriteCloser is expected, e.g. with io.Copy, bufio or gzip.
+// Write blocks while the ring is full and Read blocks while it is empty, unless it
+// wraps a non-blocking buffer, see NewByteRingFrom.
+type ByteRing struct {
+	rb *RingBuffer[byte]
+}
+
+// NewByteRing returns a new ByteRing holding up to size bytes.
+// Returns nil if size <= 0.
+func NewByteRing(size int) *ByteRing {
+	rb := NewBytes(size)
+	if rb == nil {
+		return nil
+	}
+
+	return &ByteRing{rb: rb.WithBlocking(true)}
+}
+
+// NewByteRingFrom wraps rb as a ByteRing, keeping its configuration, so the blocking
+// mode and timeouts set on rb apply to Read and Write.
+// Behavior:
+// - Without blocking, Read returns ErrIsEmpty on an empty ring, and Write writes what
+// fits and returns ErrIsFull with the number of bytes written
+// - With a timeout, Read returns ErrReadTimeout, and Write returns ErrWriteTimeout with
+// the number of bytes written so far
+// - rb can still be used directly, e.g. to check its Length or Stats
+// Returns nil if rb is nil.
+func NewByteRingFrom(rb *RingBuffer[byte]) *ByteRing {
+	if rb == nil {
+		return nil
+	}
+
+	return &ByteRing{rb: rb}
+}
+
+// Read reads up to len(p) bytes into p, following the io.Reader contract.
+// Behavior:
+// - Blocks until at least one byte is available, then returns what is there without waiting for more;
+// a wrapped buffer's timeout and non-blocking mode apply, see NewByteRingFrom
+// - Returns 0 and io.EOF once the ring is closed and drained
+// - Returns 0 and no error if p is empty
+func (b *ByteRing) Read(p []byte) (int, error) {
+	if b == nil {
+		return 0, errors.ErrNilBuffer
+	}
+
+	return b.rb.Read(p)
+}
+
+// Write writes all of p, following the io.Writer contract.
+// Behavior:
+// - Writes p chunk by chunk as readers free up space, so p may be larger than the ring;
+// a wrapped buffer's timeout and non-blocking mode apply, see NewByteRingFrom
+// - Returns len(p) and no error once every byte is written
+// - Returns io.ErrClosedPipe, with the number of bytes written so far, if the ring is closed
+func (b *ByteRing) Write(p []byte) (int, error) {
+	if b == nil {
+		return 0, errors.ErrNilBuffer
+	}
+
+	n, err := b.rb.WriteManyProgress(p, nil)
+	if stderrors.Is(err, io.EOF) {
+		err = io.ErrClosedPipe
+	}
+
+	return n, err
+}
+
+// Close closes the ring: subsequent writes return io.ErrClosedPipe, while reads
+// drain the bytes already written before returning io.EOF.
+// Blocked readers and writers are woken up.
+func (b *ByteRing) Close() error {
+	if b == nil {
+		return errors.ErrNilBuffer
+	}
+
+	return b.rb.CloseGraceful()
+}
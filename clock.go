@@ -0,0 +1,26 @@
+package ringbuffer
+
+import "time"
+
+// Clock is the source of time used for timeouts, see WithClock.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a pending call created by Clock.AfterFunc.
+type Timer interface {
+	// Stop prevents the call from running, reporting whether it was still pending.
+	Stop() bool
+}
+
+// realClock is the default Clock, backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
@@ -0,0 +1,74 @@
+package ringbuffer
+
+// Concat returns the concatenation of the byte slices queued in r, in read order,
+// without removing them. Handy to parse buffered network frames as one contiguous slice.
+// The result is a new slice, allocated once, that doesn't alias the queued slices.
+// Returns nil if r is nil or empty.
+func Concat[B ~[]byte](r *RingBuffer[B]) []byte {
+	if r == nil {
+		return nil
+	}
+
+	shared := r.peekLock()
+	defer r.peekUnlock(shared)
+
+	part1, part2 := r.view(r.Length(true))
+	return concatParts(part1, part2)
+}
+
+// ConcatDrain is like Concat, but also removes the queued slices from r,
+// waking blocked writers.
+// Returns nil if r is nil, empty, or failed with an error other than io.EOF.
+func ConcatDrain[B ~[]byte](r *RingBuffer[B]) []byte {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.readErr(true, "ConcatDrain"); err != nil {
+		return nil
+	}
+
+	n := r.Length(true)
+	if n == 0 {
+		return nil
+	}
+
+	part1, part2 := r.view(n)
+	out := concatParts(part1, part2)
+
+	r.consume(n)
+
+	r.afterRead(n)
+
+	if r.block && r.blockedWriters > 0 {
+		r.readCond.Broadcast()
+	}
+
+	return out
+}
+
+// concatParts concatenates the slices of both parts of a view into one allocation.
+func concatParts[B ~[]byte](part1, part2 []B) []byte {
+	total := 0
+	for _, parts := range [][]B{part1, part2} {
+		for _, b := range parts {
+			total += len(b)
+		}
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	out := make([]byte, 0, total)
+	for _, parts := range [][]B{part1, part2} {
+		for _, b := range parts {
+			out = append(out, b...)
+		}
+	}
+
+	return out
+}
@@ -9,6 +9,7 @@ type RingBufferConfig[T any] struct {
 	WTimeout          time.Duration
 	PreReadBlockHook  func() (obj T, tryAgain bool, success bool)
 	PreWriteBlockHook func() bool
+	Overwrite         bool
 }
 
 // IsBlocking returns whether the buffer is in blocking mode
@@ -35,3 +36,8 @@ func (c *RingBufferConfig[T]) GetPreReadBlockHook() func() (obj T, tryAgain bool
 func (c *RingBufferConfig[T]) GetPreWriteBlockHook() func() bool {
 	return c.PreWriteBlockHook
 }
+
+// IsOverwrite returns whether the buffer evicts the oldest items when full
+func (c *RingBufferConfig[T]) IsOverwrite() bool {
+	return c.Overwrite
+}
@@ -1,7 +1,9 @@
 package errors
 
 import (
+	"context"
 	"errors"
+	"fmt"
 )
 
 var (
@@ -28,4 +30,37 @@ var (
 
 	// ErrNilBuffer is returned when operations are performed on a nil buffer.
 	ErrNilBuffer = errors.New("ringbuffer is nil")
+
+	// ErrNotInitialized is returned when operations are performed on a zero-value buffer
+	// that wasn't created by New or one of the other constructors.
+	ErrNotInitialized = errors.New("ringbuffer is not initialized")
+
+	// ErrDrainTimeout is returned by CloseGracefulTimeout when items were still queued once the drain timeout elapsed.
+	ErrDrainTimeout = errors.New("drain timeout, queued items discarded")
+
+	// ErrConsumeInProgress is returned when ConsumeBatch is called while another batch is being processed.
+	ErrConsumeInProgress = errors.New("consume already in progress")
+
+	// ErrDuplicate is returned when a write is dropped because it equals the last written item.
+	ErrDuplicate = errors.New("duplicate item")
+
+	// ErrTooManyWaiters is returned when an operation would block but the cap on blocked goroutines is reached.
+	ErrTooManyWaiters = errors.New("too many blocked waiters")
+
+	// ErrReadTimeout is returned when a read times out waiting for items.
+	// It wraps context.DeadlineExceeded, so errors.Is(err, context.DeadlineExceeded) holds.
+	ErrReadTimeout = fmt.Errorf("read timeout: %w", context.DeadlineExceeded)
+
+	// ErrWriteTimeout is returned when a write times out waiting for space.
+	// It wraps context.DeadlineExceeded, so errors.Is(err, context.DeadlineExceeded) holds.
+	ErrWriteTimeout = fmt.Errorf("write timeout: %w", context.DeadlineExceeded)
+
+	// ErrSameBuffer is returned by Transfer when the source and destination are the same buffer.
+	ErrSameBuffer = errors.New("source and destination are the same buffer")
+
+	// ErrAlreadyPublished is returned by PublishExpvar when the name is already taken.
+	ErrAlreadyPublished = errors.New("expvar name already published")
+
+	// ErrCorrupted is returned by CheckInvariants when the internal state of the buffer is inconsistent.
+	ErrCorrupted = errors.New("ringbuffer invariant violated")
 )
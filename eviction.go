@@ -0,0 +1,74 @@
+package ringbuffer
+
+// EvictionPolicy chooses which queued item an overwrite mode buffer drops to make room.
+// Evict gets the queued items as a view in read order, split in two parts like
+// GetNView, and returns the index of the victim within part1 followed by part2.
+// An index out of range drops the oldest item.
+// Evict runs under the lock: it must not call back into the buffer, nor keep the view.
+type EvictionPolicy[T any] interface {
+	Evict(part1, part2 []T) int
+}
+
+// EvictionPolicyFunc adapts a function to the EvictionPolicy interface.
+type EvictionPolicyFunc[T any] func(part1, part2 []T) int
+
+// Evict calls f(part1, part2).
+func (f EvictionPolicyFunc[T]) Evict(part1, part2 []T) int {
+	return f(part1, part2)
+}
+
+// evictWithPolicy drops n items chosen one by one by the eviction policy.
+// Must be called when locked, with a policy set.
+func (r *RingBuffer[T]) evictWithPolicy(n int) {
+	for range n {
+		length := r.Length(true)
+		if length == 0 {
+			return
+		}
+
+		i := r.evictionPolicy.Evict(r.view(length))
+		if i < 0 || i >= length {
+			i = 0
+		}
+
+		item := r.removeAt(i)
+		r.discard([]T{item})
+		r.dropped.Add(1)
+	}
+}
+
+// removeAt removes and returns the i-th queued item in read order, closing the gap
+// by shifting whichever side of it is shorter.
+// Must be called when locked, with 0 <= i < Length.
+func (r *RingBuffer[T]) removeAt(i int) T {
+	length := r.Length(true)
+	at := func(j int) int { return (r.r + j) % r.size }
+
+	item := r.buf[at(i)]
+	r.bytes -= r.sizeOne(item)
+	if i < length/2 {
+		// Shift the older items one slot towards the tail
+		for j := i; j > 0; j-- {
+			r.buf[at(j)] = r.buf[at(j-1)]
+			if r.seqs != nil {
+				r.seqs[at(j)] = r.seqs[at(j-1)]
+			}
+		}
+		r.vacate(1)
+		r.r = (r.r + 1) % r.size
+	} else {
+		// Shift the newer items one slot towards the head
+		for j := i; j < length-1; j++ {
+			r.buf[at(j)] = r.buf[at(j+1)]
+			if r.seqs != nil {
+				r.seqs[at(j)] = r.seqs[at(j+1)]
+			}
+		}
+		var zero T
+		r.buf[at(length-1)] = zero
+		r.w = (r.w - 1 + r.size) % r.size
+	}
+	r.isFull = false
+
+	return item
+}
@@ -0,0 +1,34 @@
+package ringbuffer_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+)
+
+func ExamplePool() {
+	ctx := context.Background()
+	pool := ringbuffer.NewPool([]string{"conn-1", "conn-2"})
+
+	// Resources come out in the order they were put in
+	conn, _ := pool.Acquire(ctx)
+	fmt.Println("acquired", conn, "available", pool.Available())
+
+	pool.Release(conn)
+	fmt.Println("released", conn, "available", pool.Available())
+
+	// An exhausted pool makes Acquire wait until ctx is done
+	pool.Acquire(ctx)
+	pool.Acquire(ctx)
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err := pool.Acquire(timeout)
+	fmt.Println(err)
+
+	// Output:
+	// acquired conn-1 available 1
+	// released conn-1 available 2
+	// context deadline exceeded
+}
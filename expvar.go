@@ -0,0 +1,41 @@
+package ringbuffer
+
+import (
+	"expvar"
+
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+)
+
+// PublishExpvar registers the buffer stats under name in expvar, so they show up
+// in /debug/vars next to the rest of the process metrics.
+// Behavior:
+// - Publishes a map with length, capacity, writes, reads, drops, blocked_readers and blocked_writers
+// - The map is computed on every read of the variable, from atomic counters,
+// so scraping it never takes the buffer lock or slows down readers and writers
+// - The values are read one by one, they may be slightly out of sync with each other
+// - writes counts every item ever written, drops the items lost to overwrite
+// - Returns ErrAlreadyPublished if name is already taken, expvar names can't be
+// unregistered so publish each buffer once
+func (r *RingBuffer[T]) PublishExpvar(name string) error {
+	if r == nil {
+		return errors.ErrNilBuffer
+	}
+
+	if expvar.Get(name) != nil {
+		return errors.ErrAlreadyPublished
+	}
+
+	expvar.Publish(name, expvar.Func(func() any {
+		return map[string]int64{
+			"length":          r.length.Load(),
+			"capacity":        r.capacity.Load(),
+			"writes":          int64(r.writeSeq.Load()),
+			"reads":           int64(r.reads.Load()),
+			"drops":           int64(r.dropped.Load()),
+			"blocked_readers": r.waitingReaders.Load(),
+			"blocked_writers": r.waitingWriters.Load(),
+		}
+	}))
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package ringbuffer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxStringItems is the number of queued items String lists before eliding the rest.
+const maxStringItems = 32
+
+// BufferView is a snapshot of a RingBuffer, taken by Inspect.
+type BufferView[T any] struct {
+	Length   int  // Number of queued items
+	Capacity int  // Size of the underlying buffer
+	IsFull   bool // True if no item can be written without evicting or waiting
+
+	// Copy of the queued items in read order, nil unless requested.
+	// Safe to retain: later buffer operations don't change it.
+	Items []T
+}
+
+// Inspect returns a consistent snapshot of the buffer, with every field captured
+// under a single lock acquisition, unlike separate Length, Capacity and IsFull calls.
+// Behavior:
+// - Doesn't remove any item
+// - Copies the queued items into Items only if copyItems is true, applying the cloner
+// - Returns the zero BufferView for a nil buffer
+func (r *RingBuffer[T]) Inspect(copyItems bool) BufferView[T] {
+	if r == nil {
+		return BufferView[T]{}
+	}
+
+	shared := r.peekLock()
+	defer r.peekUnlock(shared)
+
+	view := BufferView[T]{
+		Length:   r.Length(true),
+		Capacity: r.size,
+		IsFull:   r.isFull,
+	}
+
+	if copyItems {
+		view.Items = make([]T, view.Length)
+		r.copyOut(view.Items)
+	}
+
+	return view
+}
+
+// String describes the buffer for log lines and test failure messages, e.g.
+// "RingBuffer(len=3 cap=8 full=false [1 2 3])".
+// Behavior:
+// - Lists the queued items in read order, formatted with %v
+// - Lists at most 32 items, followed by "..." when more are queued, so the output stays bounded
+// - The items are copied under the lock but formatted after releasing it, so their
+// own String methods may use the buffer
+func (r *RingBuffer[T]) String() string {
+	if r == nil {
+		return "RingBuffer(nil)"
+	}
+
+	shared := r.peekLock()
+	length, capacity, full := r.Length(true), r.size, r.isFull
+	items := make([]T, min(length, maxStringItems))
+	part1, part2 := r.view(len(items))
+	copy(items[copy(items, part1):], part2)
+	r.peekUnlock(shared)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "RingBuffer(len=%d cap=%d full=%t [", length, capacity, full)
+	for i, item := range items {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%v", item)
+	}
+	if length > len(items) {
+		b.WriteString(" ...")
+	}
+	b.WriteString("])")
+
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package ringbuffer
+
+import (
+	"fmt"
+
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+)
+
+// CheckInvariants validates the internal state of the buffer under the lock.
+// It is a debugging tool for tests and fuzzing, not meant for hot paths.
+// Behavior:
+// - Returns nil if the state is consistent
+// - Returns an error wrapping ErrCorrupted that describes the first violated invariant
+// - Checks the read and write positions are within [0, size), isFull is only set when they meet,
+// Length + free slots == size, leases only hold free slots, the byte total matches the sizer, and the backing slices and lock-free mirrors match the state
+func (r *RingBuffer[T]) CheckInvariants() error {
+	if r == nil {
+		return errors.ErrNilBuffer
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.checkInvariants()
+}
+
+// checkInvariants does the work of CheckInvariants.
+// Must be called when locked.
+func (r *RingBuffer[T]) checkInvariants() error {
+	switch {
+	case r.size <= 0:
+		return corrupted("size %d is not positive", r.size)
+	case len(r.buf) != r.size:
+		return corrupted("backing slice has length %d, size is %d", len(r.buf), r.size)
+	case r.r < 0 || r.r >= r.size:
+		return corrupted("read position %d out of [0, %d)", r.r, r.size)
+	case r.w < 0 || r.w >= r.size:
+		return corrupted("write position %d out of [0, %d)", r.w, r.size)
+	case r.isFull && r.r != r.w:
+		return corrupted("full with read position %d and write position %d", r.r, r.w)
+	}
+
+	length, free := r.Length(true), r.free()
+	switch {
+	case length+free != r.size:
+		return corrupted("length %d + free %d != size %d", length, free, r.size)
+	case r.seqs != nil && len(r.seqs) != r.size:
+		return corrupted("sequence slice has length %d, size is %d", len(r.seqs), r.size)
+	case r.reserved < 0 || r.reserved > r.size:
+		return corrupted("reserved capacity %d out of [0, %d]", r.reserved, r.size)
+	case r.unbounded && r.size < r.minSize:
+		return corrupted("unbounded size %d below minimum %d", r.size, r.minSize)
+	case r.blockedReaders < 0 || r.blockedWriters < 0:
+		return corrupted("negative blocked counts, %d readers and %d writers", r.blockedReaders, r.blockedWriters)
+	case r.length.Load() != int64(length):
+		return corrupted("published length %d, length is %d", r.length.Load(), length)
+	case r.capacity.Load() != int64(r.size):
+		return corrupted("published capacity %d, size is %d", r.capacity.Load(), r.size)
+	case r.leased() && r.leaseSpace() > free:
+		return corrupted("writable space %d up to the oldest lease exceeds free %d", r.leaseSpace(), free)
+	}
+
+	if r.sizer != nil {
+		part1, part2 := r.view(length)
+		if bytes := r.sizeOf(part1) + r.sizeOf(part2); r.bytes != bytes {
+			return corrupted("byte total %d, queued items measure %d", r.bytes, bytes)
+		}
+	}
+
+	return nil
+}
+
+// corrupted returns an error wrapping ErrCorrupted with the formatted description.
+func corrupted(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", errors.ErrCorrupted, fmt.Sprintf(format, args...))
+}
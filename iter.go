@@ -0,0 +1,149 @@
+package ringbuffer
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"iter"
+
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+)
+
+// ConsumeSeq returns an iterator that drains the buffer, yielding each item along with
+// its write sequence number, see GetOneSeq. Useful to check FIFO ordering and spot
+// losses in tests and diagnostics; enable WithSeqTracking to get the numbers stored at
+// write time.
+// Behavior:
+// - Each item is removed from the buffer before it is yielded
+// - Never blocks: the iteration ends once the buffer is empty
+// - Stopping the iteration early leaves the remaining items queued
+// - The lock is not held while yielding, so the loop body may use the buffer
+func (r *RingBuffer[T]) ConsumeSeq() iter.Seq2[uint64, T] {
+	return func(yield func(uint64, T) bool) {
+		if r == nil {
+			return
+		}
+
+		for {
+			seq, item, ok := r.consumeOneSeq()
+			if !ok || !yield(seq, item) {
+				return
+			}
+		}
+	}
+}
+
+// ForEachDrain removes the queued items one at a time and passes each to fn, stopping
+// at the first error fn returns, for workers that process items until one fails.
+// Behavior:
+// - Each item is removed from the buffer before fn is called, waking a waiting writer
+// - Never blocks: returns once the buffer is empty
+// - processed counts the items fn accepted; on error, failed is the item fn rejected
+// - With requeue set, the rejected item is put back at the read head, so the next read
+// returns it again; otherwise it is left out of the buffer
+// - If the rejected item can't be requeued, the returned error wraps why along with the
+// error from fn: ErrIsFull if a writer took the freed slot, io.EOF if the buffer was closed
+// - The lock is not held while fn runs, so fn may use the buffer
+func (r *RingBuffer[T]) ForEachDrain(fn func(item T) error, requeue bool) (processed int, failed T, err error) {
+	if r == nil {
+		return 0, failed, errors.ErrNilBuffer
+	}
+
+	for {
+		_, item, ok := r.consumeOneSeq()
+		if !ok {
+			return processed, failed, nil
+		}
+
+		if err := fn(item); err != nil {
+			if requeue {
+				r.mu.Lock()
+				pushErr := r.pushFront(item)
+				r.mu.Unlock()
+
+				if pushErr != nil {
+					err = fmt.Errorf("%w, requeue failed: %w", err, pushErr)
+				}
+			}
+			return processed, item, err
+		}
+		processed++
+	}
+}
+
+// DrainTo forwards the queued items, in order, to ch, bridging the buffer to code
+// consuming with select. It runs until ctx is done or the buffer is closed and drained,
+// spawning nothing: run it on a goroutine of its own.
+// Behavior:
+// - Waits for items in blocking mode, without spinning; read timeouts only restart the wait
+// - Not blocking, returns ErrIsEmpty once the buffer is empty
+// - If ctx is done while an item waits for ch to be ready, the item is put back at the
+// read head, so it isn't lost and DrainTo doesn't leak when ch is no longer read;
+// if it can't be, e.g. writers filled the buffer meanwhile, it goes to the discard hooks
+// Returns:
+// - io.EOF once the buffer is closed and drained
+// - ctx.Err() once ctx is done
+func (r *RingBuffer[T]) DrainTo(ctx context.Context, ch chan<- T) error {
+	if r == nil {
+		return errors.ErrNilBuffer
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		item, err := r.GetOneCtx(ctx)
+		if stderrors.Is(err, errors.ErrReadTimeout) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case ch <- item:
+		case <-ctx.Done():
+			return r.requeue(item, ctx.Err())
+		}
+	}
+}
+
+// requeue puts back an item removed by a consumer that failed with err, see DrainTo.
+// An item that can't be put back is handed to the discard hooks.
+// Returns err, wrapping why the item couldn't be put back if so.
+func (r *RingBuffer[T]) requeue(item T, err error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if pushErr := r.pushFront(item); pushErr != nil {
+		r.discard([]T{item})
+		r.dropped.Add(1)
+		return fmt.Errorf("%w, requeue failed: %w", err, pushErr)
+	}
+
+	return err
+}
+
+// consumeOneSeq removes the next item and returns it with its sequence number,
+// or ok false if the buffer is empty.
+func (r *RingBuffer[T]) consumeOneSeq() (seq uint64, item T, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.w == r.r && !r.isFull {
+		return 0, item, false
+	}
+
+	seq = r.headSeq()
+	item = r.clone(r.buf[r.r])
+	r.consume(1)
+
+	r.afterRead(1)
+
+	if r.block && r.blockedWriters > 0 {
+		r.readCond.Signal()
+	}
+
+	return seq, item, true
+}
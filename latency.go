@@ -0,0 +1,79 @@
+package ringbuffer
+
+import (
+	"math"
+	"time"
+)
+
+// Bucket is one bucket of the wait latency histogram, see WaitLatencyBuckets.
+type Bucket struct {
+	// UpperBound is the longest wait counted in this bucket, inclusive.
+	// The last bucket catches every longer wait and has the largest Duration.
+	UpperBound time.Duration
+	// Count is the number of waits counted in this bucket.
+	Count uint64
+}
+
+// latencyBounds are the upper bounds of the wait latency buckets, growing tenfold.
+var latencyBounds = [...]time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+	math.MaxInt64,
+}
+
+// WithLatencyTracking enables or disables recording how long blocked readers and
+// writers wait, from parking to waking up, into the histogram returned by WaitLatencyBuckets.
+// Every wake up counts as one wait, whether the operation then proceeds, waits again or times out.
+// Enabling it resets the histogram, disabling it drops it.
+func (r *RingBuffer[T]) WithLatencyTracking(enabled bool) *RingBuffer[T] {
+	r.mu.Lock()
+	r.latency = nil
+	if enabled {
+		r.latency = make([]uint64, len(latencyBounds))
+	}
+	r.mu.Unlock()
+	return r
+}
+
+// WaitLatencyBuckets returns the wait latency histogram recorded since WithLatencyTracking
+// was enabled, in increasing order of UpperBound.
+// Returns nil if latency tracking is disabled.
+func (r *RingBuffer[T]) WaitLatencyBuckets() []Bucket {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.latency == nil {
+		return nil
+	}
+
+	buckets := make([]Bucket, len(latencyBounds))
+	for i, bound := range latencyBounds {
+		buckets[i] = Bucket{UpperBound: bound, Count: r.latency[i]}
+	}
+	return buckets
+}
+
+// recordWait counts a wait that started at start and just ended, if latency tracking is enabled.
+// Must be called when locked.
+func (r *RingBuffer[T]) recordWait(start time.Time) {
+	if r.latency == nil {
+		return
+	}
+
+	waited := r.clock.Now().Sub(start)
+	for i, bound := range latencyBounds {
+		if waited <= bound {
+			r.latency[i]++
+			return
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package ringbuffer
+
+import (
+	"slices"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+)
+
+// Lease holds n items read from the buffer in place, see LeaseN.
+// The slots holding them are not reused by writes until Release is called.
+type Lease[T any] struct {
+	rb           *RingBuffer[T]
+	start        int // Slot of the first leased item
+	part1, part2 []T
+	released     bool
+}
+
+// LeaseN reads exactly n items like GetNView, but keeps writes from overwriting
+// the slots holding them until the returned lease is released.
+// Behavior:
+// - The items are removed from the buffer: Length drops by n and other readers move past them
+// - Writes that would reach the leased slots block in blocking mode, or return ErrIsFull otherwise,
+// until Release is called; this includes overwrite mode, which can't evict leased items
+// - Leases are released in any order, but the slots of a lease only become writable
+// once every older lease is released too
+// - Holding a lease for long blocks producers, so copy the items out and release promptly
+// - ClearBuffer, Flush, FlushFast, Close and resizing drop the protection of outstanding leases:
+// resizing leaves their views on the old backing array, clearing zeroes them
+// Returns:
+// - ErrInvalidLength if n <= 0 or n > buffer size
+// - ErrIsEmpty if fewer than n items are queued and not blocking
+// - ErrReadTimeout if timeout occurs
+// - io.EOF once the buffer is closed and drained
+// - io.ErrUnexpectedEOF, leaving the items queued, if the buffer is closed with fewer than n items
+func (r *RingBuffer[T]) LeaseN(n int) (*Lease[T], error) {
+	if r == nil {
+		return nil, errors.ErrNilBuffer
+	}
+
+	if n <= 0 {
+		return nil, errors.ErrInvalidLength
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.initialized() {
+		return nil, errors.ErrNotInitialized
+	}
+
+	// otherwise it will block forever
+	if n > r.size {
+		return nil, errors.ErrInvalidLength
+	}
+
+	part1, part2, err := r.getNView(n)
+	if err != nil {
+		return nil, err
+	}
+
+	// The leased slots are zeroed on Release instead, they stay in use until then
+	r.staleN = 0
+
+	l := &Lease[T]{
+		rb:    r,
+		start: (r.r - n + r.size) % r.size,
+		part1: part1,
+		part2: part2,
+	}
+	r.leases = append(r.leases, l)
+
+	return l, nil
+}
+
+// View returns the leased items, in two parts when they wrap around the buffer end.
+// Returns nil views once the lease is released.
+func (l *Lease[T]) View() (part1, part2 []T) {
+	l.rb.mu.Lock()
+	defer l.rb.mu.Unlock()
+
+	if l.released {
+		return nil, nil
+	}
+
+	return l.part1, l.part2
+}
+
+// Len returns the number of leased items.
+func (l *Lease[T]) Len() int {
+	return len(l.part1) + len(l.part2)
+}
+
+// Release hands the leased slots back to writers and wakes those waiting for them.
+// The leased slots are zeroed, so the views returned by View must not be used afterwards.
+// Calling Release again is a no-op.
+func (l *Lease[T]) Release() {
+	r := l.rb
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l.released {
+		return
+	}
+	l.released = true
+
+	// Slots of a dropped lease may have been reused, or belong to an old backing array
+	if slices.Contains(r.leases, l) {
+		clear(l.part1)
+		clear(l.part2)
+	}
+
+	// Slots only free up from the oldest lease onwards, writes reach them in that order
+	n := 0
+	for n < len(r.leases) && r.leases[n].released {
+		n++
+	}
+	if n == 0 {
+		return
+	}
+
+	clear(r.leases[:n])
+	r.leases = r.leases[n:]
+	if len(r.leases) == 0 {
+		r.leases = nil
+	}
+
+	if r.block {
+		r.readCond.Broadcast()
+	}
+}
+
+// leased reports whether outstanding leases keep writes out of some slots.
+// Must be called when locked.
+func (r *RingBuffer[T]) leased() bool {
+	return len(r.leases) > 0
+}
+
+// leaseSpace returns the number of slots writes can use before reaching the oldest
+// outstanding lease. Only meaningful when leased.
+// Must be called when locked.
+func (r *RingBuffer[T]) leaseSpace() int {
+	return (r.leases[0].start - r.w + r.size) % r.size
+}
+
+// dropLeases forgets outstanding leases, after the slots they protect were reset
+// or left behind. Releasing them later only marks them released.
+// Must be called when locked.
+func (r *RingBuffer[T]) dropLeases() {
+	clear(r.leases)
+	r.leases = nil
+}
+
+// waitForLeases waits until writing n items in overwrite mode no longer needs the
+// slots of an outstanding lease, which eviction can't free.
+// Must be called when locked and returns locked.
+func (r *RingBuffer[T]) waitForLeases(n int, location string) error {
+	n = min(n, r.size)
+	var deadline time.Time
+	for r.leased() && n > r.availableSpace() {
+		if !r.block {
+			return errors.ErrIsFull
+		}
+
+		if err := r.waitRead(&deadline, location); err != nil {
+			return err
+		}
+
+		if err := r.writeErr(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package ringbuffer
+
+import "sync"
+
+// bufferLock is the lock guarding a RingBuffer: a plain mutex, or a read-write
+// mutex whose read side peeks can share once WithRWMutex enabled it.
+// The exclusive side of sync.RWMutex costs two more atomic operations per
+// lock and unlock than sync.Mutex, so buffers that don't share peeks keep the mutex.
+type bufferLock struct {
+	mu sync.Mutex
+	rw *sync.RWMutex // Replaces mu when set
+}
+
+// Lock takes the exclusive lock. Implements sync.Locker for the condition variables.
+func (l *bufferLock) Lock() {
+	if l.rw == nil {
+		l.mu.Lock()
+		return
+	}
+
+	l.rw.Lock()
+}
+
+// Unlock releases the exclusive lock.
+func (l *bufferLock) Unlock() {
+	if l.rw == nil {
+		l.mu.Unlock()
+		return
+	}
+
+	l.rw.Unlock()
+}
+
+// rLock takes the shared lock if there is one, the exclusive lock otherwise.
+// Returns whether the shared lock was taken, to pass to rUnlock.
+func (l *bufferLock) rLock() (shared bool) {
+	if l.rw != nil {
+		l.rw.RLock()
+		return true
+	}
+
+	l.mu.Lock()
+	return false
+}
+
+// rUnlock releases the lock taken by rLock.
+func (l *bufferLock) rUnlock(shared bool) {
+	if shared {
+		l.rw.RUnlock()
+		return
+	}
+
+	l.mu.Unlock()
+}
+
+// setShared switches between the mutex and the read-write mutex.
+// Must not be called while the lock is in use.
+func (l *bufferLock) setShared(shared bool) {
+	switch {
+	case shared && l.rw == nil:
+		l.rw = new(sync.RWMutex)
+	case !shared:
+		l.rw = nil
+	}
+}
@@ -0,0 +1,253 @@
+package ringbuffer
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+)
+
+// mpmcSlot holds one item of an MPMC ring along with its turn.
+// seq == position means the slot is free for the writer claiming that position,
+// seq == position+1 means it holds the item for the reader claiming that position.
+type mpmcSlot[T any] struct {
+	seq  atomic.Uint64
+	item T
+}
+
+// MPMC is a lock-free bounded ring buffer for any number of producer and consumer
+// goroutines, using Dmitry Vyukov's bounded queue algorithm: writers and readers
+// claim positions with a CAS on a shared counter, and per-slot sequence numbers
+// tell them when the claimed slot is ready, so the fast path never takes a lock.
+//
+// TryWrite and TryRead never block. Write and Read block in blocking mode, falling
+// back to a mutex and condition variable only while the ring is full or empty.
+type MPMC[T any] struct {
+	_       cacheLinePad
+	enqueue atomic.Uint64 // next position to write
+	_       cacheLinePad
+	dequeue atomic.Uint64 // next position to read
+	_       cacheLinePad
+
+	slots []mpmcSlot[T]
+	mask  uint64
+
+	block   bool
+	closed  atomic.Bool
+	waiters atomic.Int64 // Goroutines parked, or about to park, on cond
+	mu      sync.Mutex
+	cond    *sync.Cond
+}
+
+// NewMPMC returns a new MPMC ring whose capacity is size rounded up to the next
+// power of two, and at least 2 as the algorithm requires.
+// Returns nil if size is less than or equal to 0.
+func NewMPMC[T any](size int) *MPMC[T] {
+	if size <= 0 {
+		return nil
+	}
+
+	capacity := 2
+	for capacity < size {
+		capacity <<= 1
+	}
+
+	m := &MPMC[T]{
+		slots: make([]mpmcSlot[T], capacity),
+		mask:  uint64(capacity - 1),
+	}
+	for i := range m.slots {
+		m.slots[i].seq.Store(uint64(i))
+	}
+	m.cond = sync.NewCond(&m.mu)
+
+	return m
+}
+
+// WithBlocking sets whether Write and Read wait while the ring is full or empty.
+// Must be called before the ring is shared between goroutines.
+func (m *MPMC[T]) WithBlocking(block bool) *MPMC[T] {
+	m.block = block
+	return m
+}
+
+// TryWrite writes a single item to the ring without blocking.
+// Returns ErrIsFull if the ring is full, or io.EOF once it is closed.
+func (m *MPMC[T]) TryWrite(item T) error {
+	err := m.tryWrite(item)
+	if err == nil {
+		m.wake()
+	}
+	return err
+}
+
+// tryWrite implements TryWrite, without waking blocked goroutines.
+func (m *MPMC[T]) tryWrite(item T) error {
+	if m.closed.Load() {
+		return io.EOF
+	}
+
+	pos := m.enqueue.Load()
+	for {
+		slot := &m.slots[pos&m.mask]
+		seq := slot.seq.Load()
+
+		switch dif := int64(seq) - int64(pos); {
+		case dif == 0:
+			if !m.enqueue.CompareAndSwap(pos, pos+1) {
+				pos = m.enqueue.Load()
+				continue
+			}
+
+			slot.item = item
+			slot.seq.Store(pos + 1)
+			return nil
+		case dif < 0:
+			// The slot still holds the item written a lap ago
+			return errors.ErrIsFull
+		default:
+			// Another writer claimed pos first
+			pos = m.enqueue.Load()
+		}
+	}
+}
+
+// TryRead reads a single item from the ring without blocking.
+// Returns ErrIsEmpty if the ring is empty, or io.EOF once it is closed and drained.
+func (m *MPMC[T]) TryRead() (item T, err error) {
+	item, err = m.tryRead()
+	if err == nil {
+		m.wake()
+	}
+	return item, err
+}
+
+// tryRead implements TryRead, without waking blocked goroutines.
+func (m *MPMC[T]) tryRead() (item T, err error) {
+	pos := m.dequeue.Load()
+	for {
+		slot := &m.slots[pos&m.mask]
+		seq := slot.seq.Load()
+
+		switch dif := int64(seq) - int64(pos+1); {
+		case dif == 0:
+			if !m.dequeue.CompareAndSwap(pos, pos+1) {
+				pos = m.dequeue.Load()
+				continue
+			}
+
+			var zero T
+			item = slot.item
+			slot.item = zero
+			slot.seq.Store(pos + m.mask + 1)
+			return item, nil
+		case dif < 0:
+			// The writer of pos hasn't finished yet
+			if m.closed.Load() && m.Length() == 0 {
+				return item, io.EOF
+			}
+			return item, errors.ErrIsEmpty
+		default:
+			// Another reader claimed pos first
+			pos = m.dequeue.Load()
+		}
+	}
+}
+
+// Write writes a single item to the ring.
+// Behavior:
+// - Blocks while the ring is full in blocking mode, otherwise returns ErrIsFull
+// - Returns io.EOF once the ring is closed
+func (m *MPMC[T]) Write(item T) error {
+	err := m.TryWrite(item)
+	if err != errors.ErrIsFull || !m.block {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.waiters.Add(1)
+	defer m.waiters.Add(-1)
+
+	for {
+		// Retried once registered as a waiter, so a read freeing a slot can't be missed
+		err := m.tryWrite(item)
+		if err == nil {
+			m.cond.Broadcast()
+		}
+		if err != errors.ErrIsFull {
+			return err
+		}
+		m.cond.Wait()
+	}
+}
+
+// Read reads a single item from the ring.
+// Behavior:
+// - Blocks while the ring is empty in blocking mode, otherwise returns ErrIsEmpty
+// - Returns io.EOF once the ring is closed and drained
+func (m *MPMC[T]) Read() (item T, err error) {
+	item, err = m.TryRead()
+	if err != errors.ErrIsEmpty || !m.block {
+		return item, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.waiters.Add(1)
+	defer m.waiters.Add(-1)
+
+	for {
+		// Retried once registered as a waiter, so a write can't be missed
+		item, err = m.tryRead()
+		if err == nil {
+			m.cond.Broadcast()
+		}
+		if err != errors.ErrIsEmpty {
+			return item, err
+		}
+		m.cond.Wait()
+	}
+}
+
+// Close closes the ring: writes return io.EOF, while reads drain the items already
+// written before returning io.EOF. Blocked readers and writers are woken up.
+func (m *MPMC[T]) Close() error {
+	m.closed.Store(true)
+
+	m.mu.Lock()
+	m.cond.Broadcast()
+	m.mu.Unlock()
+
+	return nil
+}
+
+// wake wakes the goroutines blocked in Write and Read, if any, after an item moved.
+func (m *MPMC[T]) wake() {
+	if m.waiters.Load() == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	m.cond.Broadcast()
+	m.mu.Unlock()
+}
+
+// Length returns the number of items that can be read.
+// The result may be stale by the time it is used.
+func (m *MPMC[T]) Length() int {
+	dequeue := m.dequeue.Load()
+	enqueue := m.enqueue.Load()
+	if enqueue < dequeue {
+		return 0
+	}
+	return int(enqueue - dequeue)
+}
+
+// Capacity returns the size of the underlying buffer.
+func (m *MPMC[T]) Capacity() int {
+	return len(m.slots)
+}
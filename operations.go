@@ -2,34 +2,111 @@ package ringbuffer
 
 import (
 	"context"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
 	"github.com/AlexsanderHamir/ringbuffer/errors"
 )
 
 // Write writes a single item to the buffer.
 // Behavior:
+// - Returns io.EOF once the buffer has been closed, even if items are still queued
 // - Blocks if buffer is full and in blocking mode
 // - Returns ErrIsFull if buffer is full and not blocking
-// - Returns context.DeadlineExceeded if timeout occurs
+// - Skips items equal to the last queued item when dedup is enabled
+// - Evicts the oldest item instead of blocking when overwrite is enabled
+// - Returns ErrWriteTimeout if timeout occurs
 // - Signals waiting readers when data is written
+// - Can't use the capacity reserved by WithReservedCapacity, see WritePriority
 func (r *RingBuffer[T]) Write(item T) error { // tested
+	return r.write(context.Background(), item, false, false)
+}
+
+// WriteCtx writes a single item like Write, but stops waiting for space as soon as
+// ctx is done, so a shutdown can unblock a producer without closing the buffer.
+// Behavior:
+// - Writes right away if there is space, even if ctx is already done
+// - Returns ctx.Err() if ctx is done while waiting, waking only the goroutines waiting on
+// the buffer, not consuming anything
+// - The write timeout still applies, whichever ends the wait first
+func (r *RingBuffer[T]) WriteCtx(ctx context.Context, item T) error {
+	return r.write(ctx, item, false, false)
+}
+
+// WritePriority writes a single item like Write, but may also use the capacity
+// reserved by WithReservedCapacity, so it only blocks or fails when the buffer is full.
+func (r *RingBuffer[T]) WritePriority(item T) error {
+	return r.write(context.Background(), item, true, false)
+}
+
+// write implements Write, WriteCtx, WritePriority and, when bounded is set, WriteBytesBounded.
+// Waiting for space stops once ctx is done.
+func (r *RingBuffer[T]) write(ctx context.Context, item T, priority, bounded bool) error {
 	if r == nil {
 		return errors.ErrNilBuffer
 	}
 
+	defer r.waitAsyncHooks()
+	defer r.unlockWriters(r.lockWriters())
+
+	var secondary *RingBuffer[T]
+	var stop func() bool
 	r.mu.Lock()
 	defer func() {
-		if r.block && r.blockedReaders > 0 {
-			r.writeCond.Signal()
+		if stop != nil {
+			stop()
 		}
+		r.signalReaders(1)
 		r.mu.Unlock()
+
+		if secondary != nil {
+			r.teeWrite(secondary, []T{item})
+		}
 	}()
 
-	if err := r.readErr(true, false, "Write"); err != nil {
+	if err := r.writeErr(); err != nil {
 		return err
 	}
 
+	if r.isDuplicate(item) {
+		if r.dedupReport {
+			return errors.ErrDuplicate
+		}
+		return nil
+	}
+
+	r.growFor(1)
+
+	var size int
+	if bounded {
+		var err error
+		if size, err = r.checkBudget(item); err != nil {
+			return err
+		}
+	}
+
+	// Replacing the newest item frees its bytes, the rest of the budget must hold item
+	if r.coalesce && r.isFull && !(bounded && r.overBudget(size-r.sizeOne(r.buf[(r.w-1+r.size)%r.size]))) {
+		r.coalesceNewest(item)
+		secondary = r.tee
+		return nil
+	}
+
+	var deadline time.Time
 	wblockAttempts := 1
-	for r.isFull {
+	for r.writeSpace(priority) == 0 && !(r.overwrite && r.less != nil) || r.overBudget(size) {
+		// A priority buffer decides what to drop once it knows where item ranks,
+		// and evicting can't free the slots of a lease
+		if r.overwrite && !r.leased() {
+			r.evict(1)
+			if r.overBudget(size) {
+				continue
+			}
+			break
+		}
+
 		if r.preWriteBlockHook != nil {
 			r.mu.Unlock()
 			tryAgain := r.preWriteBlockHook()
@@ -44,84 +121,379 @@ func (r *RingBuffer[T]) Write(item T) error { // tested
 			return errors.ErrIsFull
 		}
 
-		if !r.waitRead() {
-			return context.DeadlineExceeded
+		if ctx.Done() != nil {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if stop == nil {
+				stop = r.wakeOnDone(ctx, r.readCond)
+			}
+		}
+
+		if err := r.waitReadPriority(priority, &deadline, writeLocation(priority)); err != nil {
+			return err
+		}
+
+		if err := r.writeErr(); err != nil {
+			return err
 		}
 	}
 
-	r.buf[r.w] = item
-	r.w = (r.w + 1) % r.size
-	if r.w == r.r {
-		r.isFull = true
+	if r.less != nil {
+		r.insertSorted([]T{item})
+	} else {
+		r.buf[r.w] = item
+		r.bytes += r.sizeOne(item)
+		r.writeSeq.Add(1)
+		if r.seqs != nil {
+			r.seqs[r.w] = r.writeSeq.Load()
+		}
+		r.w = (r.w + 1) % r.size
+		if r.w == r.r {
+			r.isFull = true
+		}
 	}
+	r.markModified()
+	secondary = r.tee
 
 	return nil
 }
 
+// coalesceNewest replaces the newest queued item with item, see WithCoalesce.
+// Must be called when locked, on a full buffer.
+func (r *RingBuffer[T]) coalesceNewest(item T) {
+	last := (r.w - 1 + r.size) % r.size
+	var seq uint64
+	if r.seqs != nil {
+		seq = r.seqs[last]
+	}
+
+	if r.less != nil {
+		r.discard([]T{r.removeAt(r.size - 1)})
+		r.insertAt(r.sortedIndex(item), item, seq)
+	} else {
+		r.discard([]T{r.buf[last]})
+		r.bytes += r.sizeOne(item) - r.sizeOne(r.buf[last])
+		r.buf[last] = item
+	}
+	r.markModified()
+}
+
 // WriteMany writes multiple items to the buffer.
 // Behavior:
 // - Writes all items or none
-// - Returns ErrIsFull if buffer doesn't have enough space and not blocking
-// - Blocks until all items can be written or timeout occurs
+// - Evicts the oldest items in one step instead of blocking when overwrite is enabled
+// - Keeps only the last Capacity() items of a larger batch when overwrite is enabled
+// - Starts writing at the beginning of an empty buffer, so the batch doesn't wrap
+// - Returns ErrTooMuchDataToWrite if there are more items than the buffer size, unless overwrite
+// is enabled: they could never fit, in any mode
+// - Returns ErrIsFull if buffer doesn't have enough space and not blocking, the write may succeed later
+// - Blocks until all items can be written or returns ErrWriteTimeout when the timeout occurs
 // - Returns number of items written and any error
 // - Handles wrapping around the buffer end
+// - The items are contiguous in read order, never interleaved with other writers' items
+// - With WithMaxBatch, larger batches are written in chunks, see writeManyChunked, and are
+// only contiguous with WithContiguousBatches
 func (r *RingBuffer[T]) WriteMany(items []T) (n int, err error) { // tested
 	if len(items) == 0 {
 		return 0, nil
 	}
 
+	defer r.waitAsyncHooks()
+	defer r.unlockWriters(r.lockWriters())
+
+	if maxBatch := int(r.maxBatch.Load()); maxBatch > 0 && len(items) > maxBatch {
+		return r.writeManyChunked(items, maxBatch)
+	}
+
+	return r.writeManyBatch(items)
+}
+
+// writeManyChunked writes items in chunks of up to maxBatch items, releasing the
+// lock between chunks so other readers and writers can interleave.
+// Behavior:
+// - Not atomic: other writers' items may land between two chunks
+// - Each chunk is written like a WriteMany of its own, waking readers as it lands
+// - Stops at the first failing chunk, returning the number of items written so far
+// - A batch larger than the buffer can be written as long as readers make room
+func (r *RingBuffer[T]) writeManyChunked(items []T, maxBatch int) (n int, err error) {
+	for chunk := range slices.Chunk(items, maxBatch) {
+		written, err := r.writeManyBatch(chunk)
+		n += written
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// writeManyBatch writes all items or none under a single lock acquisition.
+func (r *RingBuffer[T]) writeManyBatch(items []T) (n int, err error) {
+	var secondary *RingBuffer[T]
 	r.mu.Lock()
 	defer func() {
-		if r.block && n > 0 {
-			r.writeCond.Signal()
+		if n > 0 {
+			r.signalReaders(n)
 		}
 		r.mu.Unlock()
+
+		if secondary != nil {
+			r.teeWrite(secondary, items)
+		}
 	}()
 
-	if err := r.readErr(true, false, "WriteMany"); err != nil {
+	if _, _, err := r.writeMany(items, "WriteMany"); err != nil {
 		return 0, err
 	}
+	n = len(items)
+	secondary = r.tee
 
-	// Calculate available free space, not total items.
-	availableSpace := r.availableSpace()
-	wblockAttempts := 1
-	// If we don't have enough free space
-	for len(items) > availableSpace {
-		if r.preWriteBlockHook != nil {
-			r.mu.Unlock()
-			tryAgain := r.preWriteBlockHook()
-			r.mu.Lock()
-			if tryAgain && wblockAttempts > 0 {
-				wblockAttempts--
-				continue
-			}
+	return n, nil
+}
+
+// WriteManyAt writes multiple items to the buffer like WriteMany, and reports
+// how they were laid out in the underlying buffer.
+// Returns:
+// - startIndex: the physical index where the first item was written
+// - wrapAt: the offset within items of the first item written at index 0, or -1 if no wrap occurred
+// - err: same errors as WriteMany
+//
+// When items is empty nothing is written and both indexes are -1, and so are they
+// for a priority buffer, see NewPriority, whose items land at their sorted positions.
+func (r *RingBuffer[T]) WriteManyAt(items []T) (startIndex, wrapAt int, err error) {
+	if len(items) == 0 {
+		return -1, -1, nil
+	}
+
+	defer r.waitAsyncHooks()
+	defer r.unlockWriters(r.lockWriters())
+
+	var secondary *RingBuffer[T]
+	r.mu.Lock()
+	defer func() {
+		if err == nil {
+			r.signalReaders(len(items))
 		}
+		r.mu.Unlock()
 
-		if !r.block {
-			return 0, errors.ErrIsFull
+		if secondary != nil {
+			r.teeWrite(secondary, items)
 		}
+	}()
+
+	startIndex, wrapAt, err = r.writeMany(items, "WriteManyAt")
+	if err == nil {
+		secondary = r.tee
+	}
+
+	return startIndex, wrapAt, err
+}
+
+// writeMany writes all items or none, returning their physical layout.
+// Must be called when locked and returns locked.
+func (r *RingBuffer[T]) writeMany(items []T, location string) (startIndex, wrapAt int, err error) {
+	if err := r.writeErr(); err != nil {
+		return -1, -1, err
+	}
+
+	// Could never fit, waiting would block forever
+	if !r.overwrite && !r.unbounded && len(items) > r.size {
+		return -1, -1, errors.ErrTooMuchDataToWrite
+	}
 
-		if !r.waitRead() {
-			return 0, context.DeadlineExceeded
+	offset := 0
+	if r.overwrite && r.less == nil {
+		if err := r.waitForLeases(len(items), location); err != nil {
+			return -1, -1, err
+		}
+		offset = r.makeRoom(items)
+		items = items[offset:]
+	} else if !r.overwrite {
+		if err := r.waitForSpace(len(items), location); err != nil {
+			return -1, -1, err
 		}
+	}
 
-		// Recalculate available space after being woken up
-		availableSpace = r.availableSpace()
+	if r.less != nil {
+		// Items land at their sorted positions, not in one run
+		r.insertSorted(items)
+		r.markModified()
+		return -1, -1, nil
 	}
 
-	// Write all items
-	if r.w+len(items) <= r.size {
-		// Can write in one go
-		copy(r.buf[r.w:], items)
-	} else {
-		// Need to wrap around
-		firstPart := r.size - r.w
-		copy(r.buf[r.w:], items[:firstPart])
-		copy(r.buf[0:], items[firstPart:])
+	r.rewindIfEmpty()
+
+	startIndex, wrapAt = r.w, -1
+	if r.w+len(items) > r.size {
+		wrapAt = offset + r.size - r.w
 	}
+
+	r.copyIn(items)
 	r.w = (r.w + len(items)) % r.size
 	r.isFull = r.w == r.r
+	r.markModified()
+
+	return startIndex, wrapAt, nil
+}
+
+// WriteManyProgress writes items chunk by chunk as space frees up, reporting progress
+// after each chunk, for long streaming writes under backpressure.
+// Behavior:
+// - Each chunk is as large as the free space, capped by WithMaxBatch if set,
+// and is written under its own lock acquisition
+// - Calls onProgress with the total number of items written so far after each chunk,
+// outside the lock; onProgress may be nil
+// - Not atomic: items of other writers may land between two chunks, unless WithContiguousBatches is set
+// - Blocks until at least one slot is free in blocking mode, each wait bounded by the write timeout
+// - Evicts the oldest items instead of waiting when overwrite is enabled
+// - Returns the total number of items written, along with ErrIsFull once the buffer
+// is full and not blocking, ErrWriteTimeout if a wait times out, or io.EOF if closed
+func (r *RingBuffer[T]) WriteManyProgress(items []T, onProgress func(written int)) (n int, err error) {
+	if r == nil {
+		return 0, errors.ErrNilBuffer
+	}
+
+	defer r.waitAsyncHooks()
+	defer r.unlockWriters(r.lockWriters())
+
+	for n < len(items) {
+		written, err := r.writeChunk(items[n:])
+		n += written
+		if written > 0 && onProgress != nil {
+			onProgress(n)
+		}
+
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// writeChunk waits for free space and writes as many of items as fit in one go.
+func (r *RingBuffer[T]) writeChunk(items []T) (n int, err error) {
+	var secondary *RingBuffer[T]
+	r.mu.Lock()
+	defer func() {
+		if n > 0 {
+			r.signalReaders(n)
+		}
+		r.mu.Unlock()
+
+		if secondary != nil {
+			r.teeWrite(secondary, items[:n])
+		}
+	}()
+
+	if err := r.writeErr(); err != nil {
+		return 0, err
+	}
+
 	n = len(items)
+	if maxBatch := int(r.maxBatch.Load()); maxBatch > 0 {
+		n = min(n, maxBatch)
+	}
+	r.growFor(n)
+
+	if !r.overwrite || r.leased() {
+		if err := r.waitForSpace(1, "WriteManyProgress"); err != nil {
+			return 0, err
+		}
+		n = min(n, r.writeSpace(false))
+	}
+
+	if err := r.tryWriteMany(items[:n]); err != nil {
+		return 0, err
+	}
+	secondary = r.tee
+
+	return n, nil
+}
+
+// WriteManyMulti writes the items of several slices to the buffer as a single operation.
+// Behavior:
+// - Writes all items of all slices or none
+// - Slices are written back to back in the order given, with no interleaving from other writers
+// - Returns ErrTooMuchDataToWrite if the combined length exceeds the buffer size
+// - Evicts the oldest items to make room when overwrite is enabled
+// - Returns ErrIsFull if buffer doesn't have enough space and not blocking
+// - Blocks until all items can be written or returns ErrWriteTimeout when the timeout occurs
+// - Returns the total number of items written and any error
+func (r *RingBuffer[T]) WriteManyMulti(parts ...[]T) (n int, err error) {
+	if r == nil {
+		return 0, errors.ErrNilBuffer
+	}
+
+	total := 0
+	for _, s := range parts {
+		total += len(s)
+	}
+
+	if total == 0 {
+		return 0, nil
+	}
+
+	defer r.waitAsyncHooks()
+	defer r.unlockWriters(r.lockWriters())
+
+	var secondary *RingBuffer[T]
+	r.mu.Lock()
+	defer func() {
+		if n > 0 {
+			r.signalReaders(n)
+		}
+		r.mu.Unlock()
+
+		if secondary != nil {
+			r.teeWrite(secondary, slices.Concat(parts...))
+		}
+	}()
+
+	r.growFor(total)
+
+	if !r.initialized() {
+		return 0, errors.ErrNotInitialized
+	}
+
+	// otherwise it will block forever
+	if total > r.size {
+		return 0, errors.ErrTooMuchDataToWrite
+	}
+
+	if err := r.writeErr(); err != nil {
+		return 0, err
+	}
+
+	if r.overwrite && r.less == nil {
+		if err := r.waitForLeases(total, "WriteManyMulti"); err != nil {
+			return 0, err
+		}
+		r.evict(total - r.availableSpace())
+	} else if !r.overwrite {
+		if err := r.waitForSpace(total, "WriteManyMulti"); err != nil {
+			return 0, err
+		}
+	}
+
+	r.rewindIfEmpty()
+
+	for _, s := range parts {
+		if r.less != nil {
+			r.insertSorted(s)
+			continue
+		}
+
+		r.copyIn(s)
+		r.w = (r.w + len(s)) % r.size
+	}
+	if r.less == nil {
+		r.isFull = r.w == r.r
+	}
+	r.markModified()
+	n = total
+	secondary = r.tee
 
 	return n, nil
 }
@@ -130,27 +502,58 @@ func (r *RingBuffer[T]) WriteMany(items []T) (n int, err error) { // tested
 // Behavior:
 // - Blocks if buffer is empty and in blocking mode
 // - Returns ErrIsEmpty if buffer is empty and not blocking
-// - Returns context.DeadlineExceeded if timeout occurs
+// - Returns ErrReadTimeout once the read timeout has elapsed since it started waiting,
+// however many times it was woken up without finding an item
 // - Signals waiting writers when data is read
 func (r *RingBuffer[T]) GetOne() (item T, err error) { // tested
+	return r.getOne(context.Background())
+}
+
+// GetOneCtx reads a single item like GetOne, but stops waiting for one as soon as
+// ctx is done, so a shutdown can unblock a consumer without closing the buffer.
+// Behavior:
+// - Reads right away if an item is queued, even if ctx is already done
+// - Returns ctx.Err() if ctx is done while waiting, waking only the goroutines waiting on
+// the buffer, not consuming anything
+// - The read timeout still applies, whichever ends the wait first
+func (r *RingBuffer[T]) GetOneCtx(ctx context.Context) (item T, err error) {
+	return r.getOne(ctx)
+}
+
+// getOne implements GetOne and GetOneCtx. Waiting for an item stops once ctx is done.
+func (r *RingBuffer[T]) getOne(ctx context.Context) (item T, err error) {
 	if r == nil {
 		return item, errors.ErrNilBuffer
 	}
 
+	var stop func() bool
 	r.mu.Lock()
 	defer func() {
+		if stop != nil {
+			stop()
+		}
 		if r.block && r.blockedWriters > 0 {
 			r.readCond.Signal()
 		}
 		r.mu.Unlock()
 	}()
 
-	if err := r.readErr(true, false, "GetOne_First"); err != nil {
-		return item, err
+	// Only a closed or failed buffer has anything to report, skip the call otherwise
+	if r.err != nil {
+		if err := r.readErr(true, "GetOne"); err != nil {
+			return item, err
+		}
 	}
 
+	var deadline time.Time
+
 	rblockAttempts := 1
 	for r.w == r.r && !r.isFull {
+		// A zero-value buffer looks empty, checked here to keep it off the fast path
+		if !r.initialized() {
+			return item, errors.ErrNotInitialized
+		}
+
 		if r.preReadBlockHook != nil {
 			r.mu.Unlock()
 			obj, tryAgain, success := r.preReadBlockHook()
@@ -169,28 +572,103 @@ func (r *RingBuffer[T]) GetOne() (item T, err error) { // tested
 			return item, errors.ErrIsEmpty
 		}
 
-		if !r.waitWrite() {
-			return item, context.DeadlineExceeded
+		if ctx.Done() != nil {
+			if err := ctx.Err(); err != nil {
+				return item, err
+			}
+			if stop == nil {
+				stop = r.wakeOnDone(ctx, r.writeCond)
+			}
+		}
+
+		if err := r.waitWrite(&deadline, "GetOne"); err != nil {
+			return item, err
 		}
 
-		if err := r.readErr(true, false, "GetOne_InnerBlock"); err != nil {
+		// The buffer may have been closed while waiting
+		if err := r.readErr(true, "GetOne"); err != nil {
 			return item, err
 		}
 	}
 
-	item = r.buf[r.r]
-	r.r = (r.r + 1) % r.size
-	r.isFull = false
+	item = r.clone(r.buf[r.r])
+	r.consume(1)
+
+	r.afterRead(1)
+
+	return item, nil
+}
+
+// GetOneSeq returns a single item like GetOne, along with its sequence number and the
+// number of items lost to overwrite since the previous GetOneSeq call, so a reader of a
+// lossy overwrite mode stream can count what it missed.
+// Behavior:
+// - Items are numbered from 1 in write order, including items lost to overwrite
+// - gap counts items evicted by overwrite, or skipped from a batch larger than the buffer
+// - Items removed by other reads, ClearBuffer or Flush are not counted as gaps
+// - The pre-read hook is not used
+// - Same errors as GetOne
+//
+// Items are queued in write order, so their sequence numbers are consecutive and the
+// head's is derived from the number of written and queued items, unless WithSeqTracking
+// stores them per slot.
+func (r *RingBuffer[T]) GetOneSeq() (item T, seq uint64, gap int, err error) {
+	if r == nil {
+		return item, 0, 0, errors.ErrNilBuffer
+	}
+
+	r.mu.Lock()
+	defer func() {
+		if err == nil && r.block && r.blockedWriters > 0 {
+			r.readCond.Signal()
+		}
+		r.mu.Unlock()
+	}()
+
+	if err := r.readErr(true, "GetOneSeq"); err != nil {
+		return item, 0, 0, err
+	}
+
+	var deadline time.Time
+	for r.w == r.r && !r.isFull {
+		if !r.block {
+			return item, 0, 0, errors.ErrIsEmpty
+		}
+
+		if err := r.waitWrite(&deadline, "GetOneSeq"); err != nil {
+			return item, 0, 0, err
+		}
+
+		if err := r.readErr(true, "GetOneSeq"); err != nil {
+			return item, 0, 0, err
+		}
+	}
+
+	seq = r.headSeq()
+	dropped := r.dropped.Load()
+	gap = int(dropped - r.droppedSeen)
+	r.droppedSeen = dropped
 
-	return item, r.readErr(true, false, "GetOne_Second")
+	item = r.clone(r.buf[r.r])
+	r.consume(1)
+
+	r.afterRead(1)
+
+	return item, seq, gap, nil
 }
 
 // GetMany returns n items from the buffer.
 // Behavior:
+// - Returns ErrInvalidLength if n <= 0 or n > buffer size, whatever the buffer state
 // - Gets all n items or blocks until it can
-// - Returns ErrIsEmpty if buffer is empty and not blocking
-// - Returns context.DeadlineExceeded if timeout occurs
+// - Once the buffer is closed, gets queued items until it is drained, then returns io.EOF
+// - Returns io.ErrUnexpectedEOF, leaving the items queued, if the buffer is closed with fewer than n items
+// - Returns ErrIsEmpty if there aren't n items available and not blocking
+// - Returns ErrReadTimeout if timeout occurs
 // - Handles wrapping around the buffer end
+// - Zeroes the slots it reads, in both segments when they wrap, so the buffer
+// doesn't keep the returned items reachable
+// - With WithMaxBatch, larger reads are done in chunks, see getNChunked
 func (r *RingBuffer[T]) GetN(n int) (items []T, err error) { // tested
 	if r == nil {
 		return nil, errors.ErrNilBuffer
@@ -200,6 +678,52 @@ func (r *RingBuffer[T]) GetN(n int) (items []T, err error) { // tested
 		return nil, errors.ErrInvalidLength
 	}
 
+	if maxBatch := int(r.maxBatch.Load()); maxBatch > 0 && n > maxBatch {
+		return r.getNChunked(n, maxBatch)
+	}
+
+	return r.getN(n)
+}
+
+// getNChunked reads n items in chunks of up to maxBatch items, releasing the
+// lock between chunks so other readers and writers can interleave.
+// Behavior:
+// - Not atomic: other readers may take items between two chunks
+// - Each chunk waits for its items like a GetN of its own
+// - Stops at the first failing chunk, returning the items read so far along with the error
+func (r *RingBuffer[T]) getNChunked(n, maxBatch int) (items []T, err error) {
+	r.mu.Lock()
+	valid := r.validReadLength(n)
+	r.mu.Unlock()
+
+	if !valid {
+		return nil, errors.ErrInvalidLength
+	}
+
+	items = make([]T, 0, n)
+	for len(items) < n {
+		chunk, err := r.getN(min(maxBatch, n-len(items)))
+		items = append(items, chunk...)
+		if err != nil {
+			if len(items) == 0 {
+				return nil, err
+			}
+			return items, err
+		}
+	}
+
+	return items, nil
+}
+
+// validReadLength reports whether the buffer can ever hold the n items a read waits for:
+// a bounded buffer holds at most its capacity, an unbounded one grows as needed.
+// Must be called when locked.
+func (r *RingBuffer[T]) validReadLength(n int) bool {
+	return n <= r.capacityLocked() || r.unbounded
+}
+
+// getN reads n items under a single lock acquisition, waiting for them if blocking.
+func (r *RingBuffer[T]) getN(n int) (items []T, err error) {
 	r.mu.Lock()
 	defer func() {
 		if r.block && r.blockedWriters > 0 {
@@ -208,24 +732,39 @@ func (r *RingBuffer[T]) GetN(n int) (items []T, err error) { // tested
 		r.mu.Unlock()
 	}()
 
-	if err := r.readErr(true, false, "GetN"); err != nil {
+	if !r.initialized() {
+		return nil, errors.ErrNotInitialized
+	}
+
+	// can never succeed, otherwise it will block forever
+	if !r.validReadLength(n) {
+		return nil, errors.ErrInvalidLength
+	}
+
+	if err := r.readErr(true, "GetN"); err != nil {
 		return nil, err
 	}
 
 	// Calculate how many items we can read
 	availableItems := r.Length(true)
 
+	var deadline time.Time
 	// Keep waiting until we can read all n items
 	for n > availableItems {
+		// Closed with fewer than n items queued, the rest will never arrive
+		if r.err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+
 		if !r.block {
 			return nil, errors.ErrIsEmpty
 		}
 
-		if !r.waitWrite() {
-			return nil, context.DeadlineExceeded
+		if err := r.waitWriteN(n, &deadline, "GetN"); err != nil {
+			return nil, err
 		}
 
-		if err := r.readErr(true, false, "GetN"); err != nil {
+		if err := r.readErr(true, "GetN"); err != nil {
 			return nil, err
 		}
 
@@ -235,32 +774,155 @@ func (r *RingBuffer[T]) GetN(n int) (items []T, err error) { // tested
 
 	// Create result slice and copy data
 	items = make([]T, n)
-	if r.w > r.r || n <= r.size-r.r {
-		// Can read in one go
-		copy(items, r.buf[r.r:r.r+n])
-	} else {
-		// Need to wrap around
-		firstPart := r.size - r.r
-		copy(items, r.buf[r.r:r.size])
-		copy(items[firstPart:], r.buf[0:n-firstPart])
-	}
+	r.copyOut(items)
+	r.consume(n)
 
-	r.r = (r.r + n) % r.size
-	r.isFull = false
+	r.afterRead(n)
 
-	return items, r.readErr(true, false, "GetN")
+	return items, nil
 }
 
-// PeekOne returns the next item without removing it from the buffer
-func (r *RingBuffer[T]) PeekOne() (item T, err error) { // tested
+// Read copies up to len(data) queued items into data and removes them from the buffer,
+// following io.Reader conventions.
+// Behavior:
+// - Copies min(len(data), available) items and returns how many were copied
+// - Blocks until at least one item is available in blocking mode
+// - Returns a partial count with a nil error when fewer items are available
+// - Returns 0 and io.EOF once the buffer is closed and drained
+// - Returns ErrIsEmpty if buffer is empty and not blocking
+// - Returns ErrReadTimeout if timeout occurs
+// - Returns 0 and no error if data is empty
+func (r *RingBuffer[T]) Read(data []T) (n int, err error) {
 	if r == nil {
-		return item, errors.ErrNilBuffer
+		return 0, errors.ErrNilBuffer
+	}
+
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	r.mu.Lock()
+	defer func() {
+		if n > 0 && r.block && r.blockedWriters > 0 {
+			r.readCond.Signal()
+		}
+		r.mu.Unlock()
+	}()
+
+	if err := r.readErr(true, "Read"); err != nil {
+		return 0, err
+	}
+
+	var deadline time.Time
+	for r.w == r.r && !r.isFull {
+		if !r.block {
+			return 0, errors.ErrIsEmpty
+		}
+
+		if err := r.waitWrite(&deadline, "Read"); err != nil {
+			return 0, err
+		}
+
+		if err := r.readErr(true, "Read"); err != nil {
+			return 0, err
+		}
+	}
+
+	n = min(len(data), r.Length(true))
+	r.copyOut(data[:n])
+	r.consume(n)
+
+	r.afterRead(n)
+
+	return n, nil
+}
+
+// DrainInto appends every queued item to dst and removes them from the buffer,
+// for flush loops that reuse one slice across calls.
+// Behavior:
+// - Grows dst at most once, to exactly the needed capacity, and copies the items
+// in at most two copies across the buffer wrap
+// - Returns the extended slice and the number of items appended
+// - Never blocks, an empty or closed and drained buffer appends nothing
+// - Items are passed through the cloner when one is set
+// - Wakes all blocked writers, since the whole buffer is free
+func (r *RingBuffer[T]) DrainInto(dst []T) ([]T, int) {
+	if r == nil {
+		return dst, 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.readErr(true, "DrainInto") != nil {
+		return dst, 0
+	}
+
+	n := r.Length(true)
+	if n == 0 {
+		return dst, 0
+	}
+
+	start := len(dst)
+	dst = slices.Grow(dst, n)[:start+n]
+	r.copyOut(dst[start:])
+	r.consume(n)
+	r.afterRead(n)
+
+	if r.block && r.blockedWriters > 0 {
+		r.readCond.Broadcast()
+	}
+
+	return dst, n
+}
+
+// Exchange takes out every queued item and leaves the buffer empty and clean in
+// one locked step, for double-buffer aggregation where each window starts afresh.
+// Behavior:
+// - Returns a newly allocated slice with all items in FIFO order, passed through
+// the cloner when one is set, or nil if the buffer is empty or closed and drained
+// - Rewinds the read and write positions to 0 and zeroes the freed slots
+// - Never blocks, and wakes all blocked writers
+// - Concurrent writes land either in the returned slice or in the next window, never both
+func (r *RingBuffer[T]) Exchange() []T {
+	if r == nil {
+		return nil
 	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if err := r.readErr(true, false, "PeekOne"); err != nil {
+	if r.readErr(true, "Exchange") != nil {
+		return nil
+	}
+
+	n := r.Length(true)
+	if n == 0 {
+		return nil
+	}
+
+	items := make([]T, n)
+	r.copyOut(items)
+	r.clearBufferLocked()
+	r.afterRead(n)
+
+	if r.block && r.blockedWriters > 0 {
+		r.readCond.Broadcast()
+	}
+
+	return items
+}
+
+// PeekOne returns the next item without removing it from the buffer
+func (r *RingBuffer[T]) PeekOne() (item T, err error) { // tested
+	if r == nil {
+		return item, errors.ErrNilBuffer
+	}
+
+	shared := r.peekLock()
+	defer r.peekUnlock(shared)
+
+	if err := r.readErr(true, "PeekOne"); err != nil {
 		return item, err
 	}
 
@@ -268,7 +930,72 @@ func (r *RingBuffer[T]) PeekOne() (item T, err error) { // tested
 		return item, errors.ErrIsEmpty
 	}
 
-	return r.buf[r.r], nil
+	return r.clone(r.buf[r.r]), nil
+}
+
+// Unread pushes item back at the read head, so the next GetOne returns it, for
+// lookahead parsing that consumed one item too many, like bufio.Reader.UnreadByte.
+// Behavior:
+// - Meant to restore the item just read, but any item can be pushed back
+// - Steps the read position back, wrapping around the buffer start
+// - A priority buffer inserts item at its sorted position instead
+// - Wakes a waiting reader
+// Returns:
+// - ErrIsFull if there is no free slot to push item back into, or outstanding leases
+// hold the slot before the read head, see LeaseN
+// - io.EOF once the buffer is closed, including by CloseGraceful and Shutdown
+func (r *RingBuffer[T]) Unread(item T) error {
+	if r == nil {
+		return errors.ErrNilBuffer
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// A draining buffer only hands out what is queued, like for writes
+	if err := r.writeErr(); err != nil {
+		return err
+	}
+
+	return r.pushFront(item)
+}
+
+// SwapHead replaces the next item to be read with newItem and returns the previous one,
+// without moving the read or write positions.
+// Useful to coalesce updates into the slot that will be read next.
+// Returns ErrIsEmpty if the buffer is empty.
+func (r *RingBuffer[T]) SwapHead(newItem T) (old T, err error) {
+	if r == nil {
+		return old, errors.ErrNilBuffer
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.writeErr(); err != nil {
+		return old, err
+	}
+
+	if r.w == r.r && !r.isFull {
+		return old, errors.ErrIsEmpty
+	}
+
+	if r.less != nil {
+		// newItem may not rank first, move it to its sorted position
+		var seq uint64
+		if r.seqs != nil {
+			seq = r.seqs[r.r]
+		}
+		old = r.removeAt(0)
+		r.insertAt(r.sortedIndex(newItem), newItem, seq)
+	} else {
+		old = r.buf[r.r]
+		r.buf[r.r] = newItem
+		r.bytes += r.sizeOne(newItem) - r.sizeOne(old)
+	}
+	r.markModified()
+
+	return old, nil
 }
 
 // PeekMany returns exactly n items without removing them from the buffer.
@@ -282,10 +1009,10 @@ func (r *RingBuffer[T]) PeekN(n int) (items []T, err error) { // tested
 		return nil, errors.ErrNilBuffer
 	}
 
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	shared := r.peekLock()
+	defer r.peekUnlock(shared)
 
-	if err := r.readErr(true, false, "PeekN"); err != nil {
+	if err := r.readErr(true, "PeekN"); err != nil {
 		return nil, err
 	}
 
@@ -300,21 +1027,67 @@ func (r *RingBuffer[T]) PeekN(n int) (items []T, err error) { // tested
 	}
 
 	items = make([]T, n)
-	if r.w > r.r {
-		copy(items, r.buf[r.r:r.r+n])
-	} else {
-		c1 := r.size - r.r
-		if c1 >= n {
-			copy(items, r.buf[r.r:r.r+n])
-		} else {
-			copy(items, r.buf[r.r:r.size])
-			copy(items[c1:], r.buf[0:n-c1])
-		}
+	r.copyOut(items)
+
+	return items, nil
+}
+
+// PeekRecentN returns a copy of the n newest items without removing them from the buffer,
+// newest first: index 0 holds the last written item. In a priority buffer, see NewPriority,
+// these are the n last items in read order, lowest priority first.
+// Returns:
+// - ErrInvalidLength if n <= 0
+// - ErrIsEmpty if the buffer is empty
+// - ErrTooMuchDataToPeek if n > Length
+func (r *RingBuffer[T]) PeekRecentN(n int) (items []T, err error) {
+	if n <= 0 {
+		return nil, errors.ErrInvalidLength
+	}
+
+	if r == nil {
+		return nil, errors.ErrNilBuffer
+	}
+
+	shared := r.peekLock()
+	defer r.peekUnlock(shared)
+
+	if err := r.readErr(true, "PeekRecentN"); err != nil {
+		return nil, err
+	}
+
+	if r.w == r.r && !r.isFull {
+		return nil, errors.ErrIsEmpty
+	}
+
+	if n > r.Length(true) {
+		return nil, errors.ErrTooMuchDataToPeek
+	}
+
+	items = make([]T, n)
+	for i := range items {
+		items[i] = r.clone(r.buf[(r.w-1-i+r.size)%r.size])
 	}
 
 	return items, nil
 }
 
+// PeekUpToN returns a copy of up to n items without removing them from the buffer.
+// Unlike PeekN it never fails: it returns min(n, Length) items, and an empty
+// slice when the buffer is empty, n <= 0, or the buffer is nil.
+func (r *RingBuffer[T]) PeekUpToN(n int) []T {
+	if r == nil || n <= 0 {
+		return []T{}
+	}
+
+	shared := r.peekLock()
+	defer r.peekUnlock(shared)
+
+	items := make([]T, min(n, r.Length(true)))
+	r.copyOut(items)
+
+	return items
+}
+
 // PeekManyView returns a view of exactly n items from the buffer without removing them.
 // The view is not a copy, but a reference to the buffer.
 // The view is valid until the buffer is modified.
@@ -327,10 +1100,10 @@ func (r *RingBuffer[T]) PeekNView(n int) (part1, part2 []T, err error) { // test
 		return nil, nil, errors.ErrInvalidLength
 	}
 
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	shared := r.peekLock()
+	defer r.peekUnlock(shared)
 
-	if err := r.readErr(true, false, "PeekManyView"); err != nil {
+	if err := r.readErr(true, "PeekManyView"); err != nil {
 		return nil, nil, err
 	}
 
@@ -354,13 +1127,41 @@ func (r *RingBuffer[T]) PeekNView(n int) (part1, part2 []T, err error) { // test
 	return part1, part2, nil
 }
 
+// PeekAllFunc calls fn with a view of all items in the buffer without removing them.
+// Behavior:
+// - The lock is held for the duration of fn, so writers can't invalidate the view
+// - Part2 is non-empty only when the queued items wrap around the buffer end
+// - Calls fn with empty views if the buffer is empty
+// - Returns fn's error, the read position is never advanced
+// - fn must not call back into the buffer, it will deadlock
+// - fn must not retain the slices after it returns
+func (r *RingBuffer[T]) PeekAllFunc(fn func(part1, part2 []T) error) error {
+	if r == nil {
+		return errors.ErrNilBuffer
+	}
+
+	shared := r.peekLock()
+	defer r.peekUnlock(shared)
+
+	if err := r.readErr(true, "PeekAllFunc"); err != nil {
+		return err
+	}
+
+	part1, part2 := r.view(r.Length(true))
+	return fn(part1, part2)
+}
+
 // GetAllView returns a view of all items in the buffer.
 // The view is not a copy, but a reference to the buffer.
 // The view is valid until the buffer is modified.
 // If the view is modified, the buffer will be modified.
 // Make sure to get the items out of the slice before the buffer is modified.
 // This is more efficient than GetAll, but less safe, depending on your use case.
-// Returns ErrIsEmpty if the buffer is empty.
+// Behavior:
+// - In blocking mode, waits until at least one item is available, like GetNView
+// - Returns ErrIsEmpty if the buffer is empty in non-blocking mode
+// - Returns ErrReadTimeout, wrapping context.DeadlineExceeded, if the read timeout passes while waiting
+// - Returns io.EOF if the buffer is closed and drained, also while waiting
 func (r *RingBuffer[T]) GetAllView() (part1, part2 []T, err error) { // tested
 	r.mu.Lock()
 	defer func() {
@@ -370,12 +1171,23 @@ func (r *RingBuffer[T]) GetAllView() (part1, part2 []T, err error) { // tested
 		r.mu.Unlock()
 	}()
 
-	if err := r.readErr(true, false, "GetAllView"); err != nil {
+	if err := r.readErr(true, "GetAllView"); err != nil {
 		return nil, nil, err
 	}
 
-	if r.w == r.r && !r.isFull {
-		return nil, nil, errors.ErrIsEmpty
+	var deadline time.Time
+	for r.w == r.r && !r.isFull {
+		if !r.block {
+			return nil, nil, errors.ErrIsEmpty
+		}
+
+		if err := r.waitWrite(&deadline, "GetAllView"); err != nil {
+			return nil, nil, err
+		}
+
+		if err := r.readErr(true, "GetAllView"); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	if r.w > r.r {
@@ -385,10 +1197,12 @@ func (r *RingBuffer[T]) GetAllView() (part1, part2 []T, err error) { // tested
 		part2 = r.buf[0:r.w]
 	}
 
-	r.r = r.w
-	r.isFull = false
+	n := len(part1) + len(part2)
+	r.consumeView(n)
 
-	return part1, part2, r.readErr(true, false, "GetAllView")
+	r.afterRead(n)
+
+	return part1, part2, nil
 }
 
 // GetManyView returns a view of exactly n items from the buffer.
@@ -400,14 +1214,109 @@ func (r *RingBuffer[T]) GetAllView() (part1, part2 []T, err error) { // tested
 // Returns:
 // - ErrInvalidLength if n <= 0 or n > buffer size
 // - ErrIsEmpty if buffer is empty and not blocking
-// - context.DeadlineExceeded if timeout occurs
+// - ErrReadTimeout if timeout occurs
+// - io.EOF once the buffer is closed and drained
+// - io.ErrUnexpectedEOF, leaving the items queued, if the buffer is closed with fewer than n items
 func (r *RingBuffer[T]) GetNView(n int) (part1, part2 []T, err error) { // tested
 	if n <= 0 {
 		return nil, nil, errors.ErrInvalidLength
 	}
 
+	r.mu.Lock()
+	defer func() {
+		if r.block && r.blockedWriters > 0 {
+			r.readCond.Signal()
+		}
+		r.mu.Unlock()
+	}()
+
+	if !r.initialized() {
+		return nil, nil, errors.ErrNotInitialized
+	}
+
+	// otherwise it will block forever
+	if n > r.size {
+		return nil, nil, errors.ErrInvalidLength
+	}
+
+	return r.getNView(n)
+}
+
+// TryGetNView returns a view of exactly n items like GetNView, but never blocks,
+// whatever the blocking mode of the buffer.
+// Returns ok false, removing nothing, if fewer than n items are available right now,
+// if n <= 0 or if the buffer is closed and drained.
+func (r *RingBuffer[T]) TryGetNView(n int) (part1, part2 []T, ok bool) {
+	if r == nil || n <= 0 {
+		return nil, nil, false
+	}
+
+	r.mu.Lock()
+	defer func() {
+		if ok && r.block && r.blockedWriters > 0 {
+			r.readCond.Signal()
+		}
+		r.mu.Unlock()
+	}()
+
+	if r.readErr(true, "TryGetNView") != nil || r.Length(true) < n {
+		return nil, nil, false
+	}
+
+	part1, part2 = r.view(n)
+
+	r.consumeView(n)
+
+	r.afterRead(n)
+
+	return part1, part2, true
+}
+
+// GetNViewGen works like GetNView but also returns the write generation observed
+// while the view was taken. Pass it to ValidateGeneration once done with the view
+// to check that no write happened in the meantime that could have overwritten its data.
+func (r *RingBuffer[T]) GetNViewGen(n int) (part1, part2 []T, gen uint64, err error) {
+	if n <= 0 {
+		return nil, nil, 0, errors.ErrInvalidLength
+	}
+
+	r.mu.Lock()
+	defer func() {
+		if r.block && r.blockedWriters > 0 {
+			r.readCond.Signal()
+		}
+		r.mu.Unlock()
+	}()
+
+	if !r.initialized() {
+		return nil, nil, 0, errors.ErrNotInitialized
+	}
+
 	// otherwise it will block forever
 	if n > r.size {
+		return nil, nil, 0, errors.ErrInvalidLength
+	}
+
+	part1, part2, err = r.getNView(n)
+	return part1, part2, r.generation.Load(), err
+}
+
+// GetUpToNView returns a view of up to n items from the buffer, without waiting for a full batch.
+// Like GetNView, the view is not a copy but a reference to the buffer, valid until the buffer is modified.
+// Behavior:
+// - Blocks until at least one item is available in blocking mode
+// - Returns a view of min(n, available) items and removes them from the buffer
+// - n may be larger than the buffer size, it only caps the batch
+// Returns:
+// - ErrInvalidLength if n <= 0
+// - ErrIsEmpty if buffer is empty and not blocking
+// - ErrReadTimeout if timeout occurs
+func (r *RingBuffer[T]) GetUpToNView(n int) (part1, part2 []T, err error) {
+	if r == nil {
+		return nil, nil, errors.ErrNilBuffer
+	}
+
+	if n <= 0 {
 		return nil, nil, errors.ErrInvalidLength
 	}
 
@@ -419,23 +1328,61 @@ func (r *RingBuffer[T]) GetNView(n int) (part1, part2 []T, err error) { // teste
 		r.mu.Unlock()
 	}()
 
-	if err := r.readErr(true, false, "GetNView"); err != nil {
+	if err := r.readErr(true, "GetUpToNView"); err != nil {
+		return nil, nil, err
+	}
+
+	var deadline time.Time
+	for r.w == r.r && !r.isFull {
+		if !r.block {
+			return nil, nil, errors.ErrIsEmpty
+		}
+
+		if err := r.waitWrite(&deadline, "GetUpToNView"); err != nil {
+			return nil, nil, err
+		}
+
+		if err := r.readErr(true, "GetUpToNView"); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	n = min(n, r.Length(true))
+	part1, part2 = r.view(n)
+
+	r.consumeView(n)
+
+	r.afterRead(n)
+
+	return part1, part2, nil
+}
+
+// getNView implements GetNView.
+// Must be called when locked and returns locked.
+func (r *RingBuffer[T]) getNView(n int) (part1, part2 []T, err error) {
+	if err := r.readErr(true, "GetNView"); err != nil {
 		return nil, nil, err
 	}
 
 	// Calculate how many items we can read
 	available := r.Length(true)
 
+	var deadline time.Time
 	for available < n || r.w == r.r && !r.isFull {
+		// Closed with fewer than n items queued, the rest will never arrive
+		if r.err == io.EOF {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+
 		if !r.block {
 			return nil, nil, errors.ErrIsEmpty
 		}
 
-		if !r.waitWrite() {
-			return nil, nil, context.DeadlineExceeded
+		if err := r.waitWriteN(n, &deadline, "GetNView"); err != nil {
+			return nil, nil, err
 		}
 
-		if err := r.readErr(true, false, "GetNView"); err != nil {
+		if err := r.readErr(true, "GetNView"); err != nil {
 			return nil, nil, err
 		}
 
@@ -450,21 +1397,570 @@ func (r *RingBuffer[T]) GetNView(n int) (part1, part2 []T, err error) { // teste
 		part2 = r.buf[0 : n-len(part1)]
 	}
 
+	r.consumeView(n)
+
+	r.afterRead(n)
+
+	return part1, part2, nil
+}
+
+// ConsumeBatch reads up to n items and passes them to fn, removing them from the
+// buffer only if fn returns nil. If fn returns an error the items stay queued and
+// will be handed out again by the next read.
+// Behavior:
+// - Blocks until at least one item is available in blocking mode
+// - Returns ErrIsEmpty if buffer is empty and not blocking
+// - Returns ErrReadTimeout if timeout occurs
+// - Returns ErrConsumeInProgress if another ConsumeBatch call is running
+// - Signals waiting writers when the batch is committed
+//
+// The lock is not held while fn runs, so fn may use the buffer freely.
+// The slice passed to fn is reused between calls and must not be retained.
+// Concurrency contract: the batch is only protected from other ConsumeBatch calls.
+// Other readers (GetOne, GetN, views...) running concurrently can still consume the
+// same items, so ConsumeBatch must be the buffer's single consumer.
+func (r *RingBuffer[T]) ConsumeBatch(n int, fn func(items []T) error) error {
+	if r == nil {
+		return errors.ErrNilBuffer
+	}
+
+	if n <= 0 {
+		return errors.ErrInvalidLength
+	}
+
+	r.mu.Lock()
+	if r.consuming {
+		r.mu.Unlock()
+		return errors.ErrConsumeInProgress
+	}
+
+	if err := r.readErr(true, "ConsumeBatch"); err != nil {
+		r.mu.Unlock()
+		return err
+	}
+
+	var deadline time.Time
+	for r.w == r.r && !r.isFull {
+		if !r.block {
+			r.mu.Unlock()
+			return errors.ErrIsEmpty
+		}
+
+		if err := r.waitWrite(&deadline, "ConsumeBatch"); err != nil {
+			r.mu.Unlock()
+			return err
+		}
+
+		if err := r.readErr(true, "ConsumeBatch"); err != nil {
+			r.mu.Unlock()
+			return err
+		}
+	}
+
+	n = min(n, r.Length(true))
+	if cap(r.consumeBuf) < n {
+		r.consumeBuf = make([]T, n)
+	}
+	batch := r.consumeBuf[:n]
+	r.copyOut(batch)
+	r.consuming = true
+	r.mu.Unlock()
+
+	fnErr := fn(batch)
+
+	r.mu.Lock()
+	defer func() {
+		if r.block && r.blockedWriters > 0 {
+			r.readCond.Signal()
+		}
+		r.mu.Unlock()
+	}()
+
+	r.consuming = false
+	clear(batch)
+
+	if fnErr != nil {
+		return fnErr
+	}
+
+	// The buffer was closed, flushed or reset while fn was running.
+	if r.Length(true) < n {
+		return r.readErr(true, "ConsumeBatch")
+	}
+
+	r.consume(n)
+	r.afterRead(n)
+
+	return nil
+}
+
+// writeLocation names the single item write for diagnostics.
+func writeLocation(priority bool) string {
+	if priority {
+		return "WritePriority"
+	}
+	return "Write"
+}
+
+// waitForSpace waits until n slots are free, running the pre-write hook first.
+// location names the waiting operation for diagnostics and the timeout hook.
+// Must be called when locked and returns locked.
+func (r *RingBuffer[T]) waitForSpace(n int, location string) error {
+	r.growFor(n)
+
+	var deadline time.Time
+	wblockAttempts := 1
+	for n > r.writeSpace(false) {
+		if r.preWriteBlockHook != nil {
+			r.mu.Unlock()
+			tryAgain := r.preWriteBlockHook()
+			r.mu.Lock()
+			if tryAgain && wblockAttempts > 0 {
+				wblockAttempts--
+				continue
+			}
+		}
+
+		if !r.block {
+			return errors.ErrIsFull
+		}
+
+		if err := r.waitReadPriority(false, &deadline, location); err != nil {
+			return err
+		}
+
+		if err := r.writeErr(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// teeWrite copies items that were just written into the secondary buffer.
+// The write never blocks: it is dropped with ErrIsFull when the secondary doesn't
+// have room, unless the secondary is in overwrite mode. Failures are reported to
+// the tee error hook, if any. The secondary's own tee is not followed.
+// Must be called without holding the lock.
+func (r *RingBuffer[T]) teeWrite(secondary *RingBuffer[T], items []T) {
+	secondary.mu.Lock()
+	err := secondary.tryWriteMany(items)
+	if err == nil {
+		secondary.signalReaders(len(items))
+	}
+	secondary.mu.Unlock()
+
+	if err == nil {
+		return
+	}
+
+	r.mu.Lock()
+	hook := r.onTeeError
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook(err)
+	}
+}
+
+// tryWriteMany writes all items or none, without ever waiting for space.
+// Must be called when locked and returns locked.
+func (r *RingBuffer[T]) tryWriteMany(items []T) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	if err := r.writeErr(); err != nil {
+		return err
+	}
+
+	r.growFor(len(items))
+
+	// Evicting can't free the slots of a lease
+	if (!r.overwrite || r.leased() && r.less == nil) && len(items) > r.writeSpace(false) {
+		return errors.ErrIsFull
+	}
+
+	switch {
+	case r.less != nil:
+		r.insertSorted(items)
+	case r.overwrite:
+		items = items[r.makeRoom(items):]
+		fallthrough
+	default:
+		r.rewindIfEmpty()
+		r.copyIn(items)
+		r.w = (r.w + len(items)) % r.size
+		r.isFull = r.w == r.r
+	}
+	r.markModified()
+
+	return nil
+}
+
+// makeRoom evicts the oldest items so that items fit without blocking.
+// Items that could never fit are discarded upfront, and the offset of
+// the first item to write is returned.
+// Must be called when locked.
+func (r *RingBuffer[T]) makeRoom(items []T) (offset int) {
+	r.growFor(len(items))
+
+	if len(items) > r.size {
+		offset = len(items) - r.size
+		r.discard(items[:offset])
+		r.writeSeq.Add(uint64(offset))
+		r.dropped.Add(uint64(offset))
+	}
+
+	r.evict(len(items) - offset - r.availableSpace())
+	return offset
+}
+
+// evict drops the n oldest items, advancing the read position in a single step,
+// or the n items chosen by the eviction policy if one is set.
+// Must be called when locked.
+func (r *RingBuffer[T]) evict(n int) {
+	if n <= 0 {
+		return
+	}
+
+	if r.evictionPolicy != nil {
+		r.evictWithPolicy(n)
+		return
+	}
+
+	part1, part2 := r.view(n)
+	r.discard(part1)
+	r.discard(part2)
+	r.dropped.Add(uint64(n))
+
+	r.consume(n)
+}
+
+// discard hands items dropped by the buffer to the discard hooks.
+// Must be called when locked.
+func (r *RingBuffer[T]) discard(items []T) {
+	if len(items) == 0 {
+		return
+	}
+
+	if r.asyncHooks != nil {
+		r.discardAsync(items)
+		return
+	}
+
+	if r.onDiscardMany != nil {
+		r.onDiscardMany(items)
+	}
+
+	if r.onDiscard != nil {
+		for _, item := range items {
+			r.onDiscard(item)
+		}
+	}
+}
+
+// discardAsync queues a call of the discard hooks with a copy of items, see WithAsyncHooks.
+// Must be called when locked.
+func (r *RingBuffer[T]) discardAsync(items []T) {
+	one, many := r.onDiscard, r.onDiscardMany
+	if one == nil && many == nil {
+		return
+	}
+
+	items = slices.Clone(items)
+	call := func() {
+		if many != nil {
+			many(items)
+		}
+
+		if one != nil {
+			for _, item := range items {
+				one(item)
+			}
+		}
+	}
+
+	// Calls queue behind the backlog, so they keep running in order
+	q := r.asyncHooks
+	if len(q.backlog) == 0 {
+		select {
+		case q.calls <- call:
+			return
+		default:
+		}
+	}
+
+	if r.asyncHooksDrop {
+		r.logVerbose("async hooks queue full, dropped a call for %d items", len(items))
+		return
+	}
+
+	// The write waits for it once unlocked, see waitAsyncHooks
+	q.backlog = append(q.backlog, call)
+	r.asyncHooksBehind.Store(true)
+}
+
+// hookQueue holds the discard hook calls waiting for the dispatcher, see WithAsyncHooks.
+type hookQueue struct {
+	calls    chan func()
+	backlog  []func()   // Calls queued after calls filled up, guarded by the buffer lock
+	caughtUp *sync.Cond // Broadcast once the dispatcher took the backlog
+}
+
+// runHookBacklog runs the calls of the backlog of q once the calls queued before them ran.
+// Must be called without holding the lock.
+func (r *RingBuffer[T]) runHookBacklog(q *hookQueue) {
+	for {
+		r.mu.Lock()
+		if len(q.calls) > 0 || len(q.backlog) == 0 {
+			r.mu.Unlock()
+			return
+		}
+		backlog := q.backlog
+		q.backlog = nil
+		if r.asyncHooks == q || r.asyncHooks == nil {
+			r.asyncHooksBehind.Store(false)
+		}
+		q.caughtUp.Broadcast()
+		r.mu.Unlock()
+
+		for _, call := range backlog {
+			call()
+		}
+	}
+}
+
+// waitAsyncHooks waits until the dispatcher took the discard hook calls queued past
+// the async hook queue, so writes evicting faster than the hooks run slow down instead
+// of queuing calls without limit, see WithAsyncHooks.
+// Must be called without holding the lock.
+func (r *RingBuffer[T]) waitAsyncHooks() {
+	if !r.asyncHooksBehind.Load() {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Close hands the queue over to the dispatcher, which still takes the backlog
+	q := r.asyncHooks
+	for q != nil && len(q.backlog) > 0 {
+		q.caughtUp.Wait()
+	}
+}
+
+// view returns the n items starting at the read position as up to two segments.
+// Must be called when locked, with n <= Length.
+func (r *RingBuffer[T]) view(n int) (part1, part2 []T) {
+	if r.r+n <= r.size {
+		return r.buf[r.r : r.r+n], nil
+	}
+
+	part1 = r.buf[r.r:r.size]
+	return part1, r.buf[0 : n-len(part1)]
+}
+
+// rewindIfEmpty moves the read and write positions back to the start of an
+// empty buffer, so the next batch is copied in one go instead of wrapping.
+// Must be called when locked.
+func (r *RingBuffer[T]) rewindIfEmpty() {
+	if r.w == r.r && !r.isFull && !r.leased() {
+		r.r = 0
+		r.w = 0
+	}
+}
+
+// pushFront puts item back at the read head, so the next read returns it, as if
+// the last read was undone. A priority buffer inserts it at its sorted position.
+// A buffer draining after CloseGraceful or Shutdown takes the item back: it was queued
+// before, so the drain still only delivers what was queued. Unread rejects it, like writes.
+// Returns ErrIsFull if no slot is free, or outstanding leases hold the slot before
+// the read head, io.EOF once the buffer is closed, and the buffer error if any.
+// Must be called when locked.
+func (r *RingBuffer[T]) pushFront(item T) error {
+	if r.closed {
+		return io.EOF
+	}
+
+	if err := r.writeErr(); err != nil && err != io.EOF {
+		return err
+	}
+
+	if r.availableSpace() == 0 || r.leased() {
+		return errors.ErrIsFull
+	}
+
+	// The item precedes the queued ones in write order
+	seq := r.writeSeq.Load()
+	if r.seqs != nil && r.Length(true) > 0 {
+		seq = r.seqs[r.r] - 1
+	}
+
+	if r.less != nil {
+		r.insertAt(r.sortedIndex(item), item, seq)
+	} else {
+		r.r = (r.r - 1 + r.size) % r.size
+		r.buf[r.r] = item
+		r.bytes += r.sizeOne(item)
+		if r.seqs != nil {
+			r.seqs[r.r] = seq
+		}
+		r.isFull = r.w == r.r
+	}
+	r.markModified()
+	r.signalReaders(1)
+
+	return nil
+}
+
+// copyIn copies items into the buffer starting at the write position,
+// wrapping around the buffer end if needed. It does not advance r.w,
+// but numbers the items in the write sequence.
+func (r *RingBuffer[T]) copyIn(items []T) {
+	if r.w+len(items) <= r.size {
+		// Can write in one go
+		copy(r.buf[r.w:], items)
+	} else {
+		// Need to wrap around
+		firstPart := r.size - r.w
+		copy(r.buf[r.w:], items[:firstPart])
+		copy(r.buf[0:], items[firstPart:])
+	}
+	r.bytes += r.sizeOf(items)
+
+	if r.seqs != nil {
+		for i := range items {
+			r.seqs[(r.w+i)%r.size] = r.writeSeq.Load() + uint64(i) + 1
+		}
+	}
+	r.writeSeq.Add(uint64(len(items)))
+}
+
+// headSeq returns the sequence number of the next item to read.
+// Must be called when locked, on a non-empty buffer.
+func (r *RingBuffer[T]) headSeq() uint64 {
+	if r.seqs != nil {
+		return r.seqs[r.r]
+	}
+
+	// Queued items are in write order, so their numbers are consecutive
+	return r.writeSeq.Load() - uint64(r.Length(true)) + 1
+}
+
+// copyOut copies len(dst) items into dst starting at the read position,
+// wrapping around the buffer end if needed. It does not advance r.r.
+// Items are passed through the cloner when one is set.
+func (r *RingBuffer[T]) copyOut(dst []T) {
+	n := len(dst)
+	if r.r+n <= r.size {
+		// Can read in one go
+		copy(dst, r.buf[r.r:r.r+n])
+	} else {
+		// Need to wrap around
+		firstPart := r.size - r.r
+		copy(dst, r.buf[r.r:r.size])
+		copy(dst[firstPart:], r.buf[0:n-firstPart])
+	}
+
+	if r.cloner != nil {
+		for i := range dst {
+			dst[i] = r.cloner(dst[i])
+		}
+	}
+}
+
+// consume removes the n items at the read head, already copied out, and zeroes their
+// slots so the buffer doesn't keep them reachable.
+// Must be called when locked, with n <= Length.
+func (r *RingBuffer[T]) consume(n int) {
+	r.clearStale()
+	r.unsize(n)
+	r.vacate(n)
 	r.r = (r.r + n) % r.size
 	r.isFull = false
+}
+
+// consumeView removes the n items at the read head, handed out as a view. Their slots
+// are zeroed by the next modification instead, since the view stays valid until then.
+// Must be called when locked, with n <= Length.
+func (r *RingBuffer[T]) consumeView(n int) {
+	r.clearStale()
+	r.unsize(n)
+	r.staleAt, r.staleN = r.r, n
+	r.r = (r.r + n) % r.size
+	r.isFull = false
+}
+
+// clearStale zeroes the slots handed out by the last view read that no write reused
+// since, ending the validity of the view.
+// Must be called when locked, before moving the read position.
+func (r *RingBuffer[T]) clearStale() {
+	if r.staleN == 0 {
+		return
+	}
+
+	var zero T
+	length := r.Length(true)
+	for i := range r.staleN {
+		at := (r.staleAt + i) % r.size
+		if (at-r.r+r.size)%r.size >= length {
+			r.buf[at] = zero
+		}
+	}
+	r.staleN = 0
+}
+
+// vacate zeroes the n slots starting at the read position, in both segments when they
+// wrap around the buffer end, so items already copied out don't stay reachable from
+// the buffer and can be garbage collected.
+// Must be called when locked, before advancing the read position.
+func (r *RingBuffer[T]) vacate(n int) {
+	if end := r.r + n; end <= r.size {
+		clear(r.buf[r.r:end])
+	} else {
+		clear(r.buf[r.r:])
+		clear(r.buf[:end-r.size])
+	}
+}
 
-	return part1, part2, r.readErr(true, false, "GetNView")
+// clone returns item passed through the cloner, or item itself when none is set.
+func (r *RingBuffer[T]) clone(item T) T {
+	if r.cloner == nil {
+		return item
+	}
+	return r.cloner(item)
 }
 
-// availableSpace returns the number of free slots in the buffer.
+// isDuplicate reports whether item equals the most recently written item
+// still queued in the buffer. Always false when dedup is disabled or the buffer is empty.
+func (r *RingBuffer[T]) isDuplicate(item T) bool {
+	if r.dedupEq == nil || (r.w == r.r && !r.isFull) {
+		return false
+	}
+
+	last := (r.w - 1 + r.size) % r.size
+	return r.dedupEq(r.buf[last], item)
+}
+
+// availableSpace returns the number of free slots writes can use,
+// excluding those held by outstanding leases.
 func (r *RingBuffer[T]) availableSpace() int {
-	if r.isFull {
-		return 0
+	if r.leased() {
+		return r.leaseSpace()
 	}
-	if r.w >= r.r {
-		return r.size - r.w + r.r
+	return r.free()
+}
+
+// writeSpace returns the space available to a write: all of it for priority
+// writes and in overwrite mode, otherwise the space left outside the reserved capacity.
+// Must be called when locked.
+func (r *RingBuffer[T]) writeSpace(priority bool) int {
+	if priority || r.overwrite {
+		return r.availableSpace()
 	}
-	return r.r - r.w
+
+	return max(r.availableSpace()-r.reserved, 0)
 }
 
 // wake up one reader
@@ -0,0 +1,116 @@
+package ringbuffer
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+)
+
+// Pool is a bounded resource pool, or counting semaphore, built on a blocking RingBuffer.
+// Acquire takes a resource out of the pool, waiting for one to be released if
+// all are in use, and Release puts it back.
+// With Pool[struct{}] it limits concurrency to the number of pre-filled tokens.
+type Pool[T any] struct {
+	rb *RingBuffer[T]
+}
+
+// NewPool returns a new Pool pre-filled with items, whose capacity is len(items).
+// Returns nil if items is empty.
+func NewPool[T any](items []T) *Pool[T] {
+	rb := New[T](len(items))
+	if rb == nil {
+		return nil
+	}
+
+	rb.WithBlocking(true)
+	if _, err := rb.WriteMany(items); err != nil {
+		return nil
+	}
+
+	return &Pool[T]{rb: rb}
+}
+
+// Acquire takes a resource out of the pool.
+// Behavior:
+// - Returns right away if a resource is available
+// - Otherwise blocks until one is released or ctx is done
+// - Returns ctx.Err() if ctx is done while waiting for a resource
+// - Returns io.EOF if the pool is closed
+func (p *Pool[T]) Acquire(ctx context.Context) (item T, err error) {
+	if p == nil {
+		return item, errors.ErrNilBuffer
+	}
+
+	r := p.rb
+	var stop func() bool
+	r.mu.Lock()
+	defer func() {
+		if stop != nil {
+			stop()
+		}
+		r.mu.Unlock()
+	}()
+
+	for r.w == r.r && !r.isFull {
+		if err := r.readErr(true, "Acquire"); err != nil {
+			return item, err
+		}
+
+		if ctx.Done() != nil {
+			if err := ctx.Err(); err != nil {
+				return item, err
+			}
+			// Wake the waiters so the one waiting on ctx notices it's done
+			if stop == nil {
+				stop = r.wakeOnDone(ctx, r.writeCond)
+			}
+		}
+
+		if err := r.waitWriteUntil(time.Time{}, "Acquire"); err != nil {
+			return item, err
+		}
+	}
+
+	item = r.buf[r.r]
+	r.consume(1)
+	r.afterRead(1)
+
+	return item, nil
+}
+
+// Release puts a resource back into the pool, waking one waiting Acquire.
+// Returns ErrIsFull if the pool already holds all its resources,
+// which means item was released more times than it was acquired.
+func (p *Pool[T]) Release(item T) error {
+	if p == nil {
+		return errors.ErrNilBuffer
+	}
+
+	r := p.rb
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.tryWriteMany([]T{item}); err != nil {
+		return err
+	}
+	r.signalReaders(1)
+
+	return nil
+}
+
+// Available returns the number of resources that can be acquired without blocking.
+func (p *Pool[T]) Available() int {
+	return p.rb.Length(false)
+}
+
+// Capacity returns the total number of resources managed by the pool.
+func (p *Pool[T]) Capacity() int {
+	return p.rb.Capacity()
+}
+
+// Close closes the pool, waking every waiting Acquire with io.EOF.
+// Resources still in the pool are dropped.
+func (p *Pool[T]) Close() error {
+	return p.rb.Close()
+}
@@ -0,0 +1,81 @@
+package ringbuffer
+
+// WithPrefetch keeps the buffer warm for the next consumer: once a read leaves
+// threshold items or fewer, fetch is called and the items it returns are written.
+// Behavior:
+// - fetch runs on its own goroutine, outside the lock, so reads never wait for it;
+// Wait waits for a running prefetch
+// - Only one prefetch runs at a time: reads crossing the threshold meanwhile start none
+// - The fetched items are written without blocking: the ones that don't fit are handed
+// to the discard hooks, or evict the oldest items when overwrite is enabled
+// - Items that never fit aren't counted in Stats().Dropped, which only counts written items lost
+// - Nothing is written once the buffer is closed
+// - Passing a nil fetch disables prefetching
+func (r *RingBuffer[T]) WithPrefetch(threshold int, fetch func() []T) *RingBuffer[T] {
+	r.mu.Lock()
+	r.prefetch = fetch
+	r.prefetchThreshold = threshold
+	r.mu.Unlock()
+	return r
+}
+
+// startPrefetch starts a prefetch if one is set, due, and not already running.
+// Must be called when locked.
+func (r *RingBuffer[T]) startPrefetch() {
+	fetch := r.prefetch
+	if fetch == nil || r.Length(true) > r.prefetchThreshold || r.err != nil {
+		return
+	}
+
+	if !r.prefetching.CompareAndSwap(false, true) {
+		return
+	}
+
+	r.goroutines.Add(1)
+	go func() {
+		defer r.goroutines.Done()
+		defer r.prefetching.Store(false)
+
+		if items := fetch(); len(items) > 0 {
+			r.writePrefetched(items)
+		}
+	}()
+}
+
+// writePrefetched writes as many of the fetched items as fit without waiting.
+func (r *RingBuffer[T]) writePrefetched(items []T) {
+	defer r.waitAsyncHooks()
+	defer r.unlockWriters(r.lockWriters())
+
+	var secondary *RingBuffer[T]
+	r.mu.Lock()
+	defer func() {
+		if len(items) > 0 {
+			r.signalReaders(len(items))
+		}
+		r.mu.Unlock()
+
+		if secondary != nil {
+			r.teeWrite(secondary, items)
+		}
+	}()
+
+	if r.writeErr() != nil {
+		items = nil
+		return
+	}
+
+	r.growFor(len(items))
+	if !r.overwrite || r.leased() {
+		fit := min(len(items), r.writeSpace(false))
+		// Never written, so not counted as dropped: writeSeq and the GetOneSeq gaps stay exact
+		r.discard(items[fit:])
+		items = items[:fit]
+	}
+
+	if len(items) == 0 || r.tryWriteMany(items) != nil {
+		items = nil
+		return
+	}
+	secondary = r.tee
+}
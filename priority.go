@@ -0,0 +1,99 @@
+package ringbuffer
+
+import "sort"
+
+// NewPriority returns a new RingBuffer of the given size that keeps its items sorted
+// by less, so reads return the highest-priority item first: the one less orders first.
+// Behavior:
+// - Writes insert each item at its sorted position, shifting the queued items on the
+// shorter side of it within the ring: O(n) per item instead of O(1)
+// - Items of equal priority are read in write order
+// - A full buffer makes writes fail with ErrIsFull, block in blocking mode, or in
+// overwrite mode drop the lowest-priority item, which is the written one if no queued
+// item ranks lower. Dropped items go to the discard hooks.
+// - Reads, peeks and views are unchanged and see the items in priority order
+// - Sequence numbers are only meaningful with WithSeqTracking, items aren't read in write order
+// - Returns nil if size <= 0 or less is nil
+func NewPriority[T any](size int, less func(a, b T) bool) *RingBuffer[T] {
+	if less == nil {
+		return nil
+	}
+
+	rb := New[T](size)
+	if rb == nil {
+		return nil
+	}
+
+	rb.less = less
+	return rb
+}
+
+// insertSorted writes items one by one at their sorted position, see NewPriority.
+// A full buffer drops its lowest-priority item, or the written one if no queued item
+// ranks lower, so callers not overwriting must make room for all items first.
+// Must be called when locked.
+func (r *RingBuffer[T]) insertSorted(items []T) {
+	for _, item := range items {
+		seq := r.writeSeq.Add(1)
+
+		if r.availableSpace() == 0 {
+			// Every slot may be queued or leased
+			length := r.Length(true)
+			last := (r.w - 1 + r.size) % r.size
+			if length == 0 || !r.less(item, r.buf[last]) {
+				r.discard([]T{item})
+				r.dropped.Add(1)
+				continue
+			}
+
+			r.discard([]T{r.removeAt(length - 1)})
+			r.dropped.Add(1)
+		}
+
+		r.insertAt(r.sortedIndex(item), item, seq)
+	}
+}
+
+// sortedIndex returns the position in read order where item goes: after every
+// queued item it doesn't rank before, so equal items keep their write order.
+// Must be called when locked.
+func (r *RingBuffer[T]) sortedIndex(item T) int {
+	return sort.Search(r.Length(true), func(j int) bool {
+		return r.less(item, r.buf[(r.r+j)%r.size])
+	})
+}
+
+// insertAt inserts item as the i-th queued item in read order, making room by
+// shifting whichever side of it is shorter. It is the inverse of removeAt.
+// Must be called when locked, with 0 <= i <= Length < size.
+func (r *RingBuffer[T]) insertAt(i int, item T, seq uint64) {
+	length := r.Length(true)
+	at := func(j int) int { return (r.r + j) % r.size }
+
+	if i < length/2 && !r.leased() {
+		// Shift the older items one slot towards the head, unless it is leased
+		r.r = (r.r - 1 + r.size) % r.size
+		for j := 0; j < i; j++ {
+			r.buf[at(j)] = r.buf[at(j+1)]
+			if r.seqs != nil {
+				r.seqs[at(j)] = r.seqs[at(j+1)]
+			}
+		}
+	} else {
+		// Shift the newer items one slot towards the tail
+		for j := length; j > i; j-- {
+			r.buf[at(j)] = r.buf[at(j-1)]
+			if r.seqs != nil {
+				r.seqs[at(j)] = r.seqs[at(j-1)]
+			}
+		}
+		r.w = (r.w + 1) % r.size
+	}
+
+	r.buf[at(i)] = item
+	r.bytes += r.sizeOne(item)
+	if r.seqs != nil {
+		r.seqs[at(i)] = seq
+	}
+	r.isFull = r.w == r.r
+}
@@ -2,16 +2,19 @@ package ringbuffer
 
 import (
 	"io"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/AlexsanderHamir/ringbuffer/config"
 	"github.com/AlexsanderHamir/ringbuffer/errors"
 )
 
-// RingBuffer is a circular buffer that implements io.ReaderWriter interface.
-// It operates like a buffered pipe, where data is written to a RingBuffer
-// and can be read back from another goroutine.
+// RingBuffer is a circular buffer that operates like a buffered pipe, where data is
+// written to a RingBuffer and can be read back from another goroutine.
+// Its Read follows io.Reader conventions, so a RingBuffer[byte] is an io.Reader;
+// wrap it in a ByteRing for a full io.ReadWriteCloser, see NewByteRingFrom.
 // It is safe to concurrently read and write RingBuffer.
 //
 // Key features:
@@ -30,12 +33,41 @@ type RingBuffer[T any] struct {
 	block     bool
 	rTimeout  time.Duration // Applies to writes (waits for the read condition)
 	wTimeout  time.Duration // Applies to read (wait for the write condition)
-	mu        sync.Mutex
+	mu        bufferLock
 	readCond  *sync.Cond // Signaled when data has been read.
 	writeCond *sync.Cond // Signaled when data has been written.
 
-	blockedReaders int
-	blockedWriters int
+	blockedReaders  int
+	blockedWriters  int
+	lengthWaiters   int // Goroutines parked in WaitForLength
+	bulkReaders     int // Blocked readers waiting for more than one item
+	priorityWriters int // Blocked writers allowed to use the reserved capacity
+
+	stateCond    *sync.Cond // Broadcast on every change while stateWaiters > 0, see WaitFor
+	stateWaiters int
+
+	// Timers shared by the timed waits on readCond, writeCond and stateCond
+	readTimer  waitTimer
+	writeTimer waitTimer
+	stateTimer waitTimer
+
+	// Caps on simultaneously blocked goroutines, 0 means unlimited
+	maxBlockedReaders int
+	maxBlockedWriters int
+
+	// Incremented whenever slots are written or cleared, see Generation.
+	generation atomic.Uint64
+
+	// Mirror of Length kept up to date under the lock, see IsEmptyFast.
+	length atomic.Int64
+
+	// Mirrors of size and the blocked counts kept up to date under the lock, see PublishExpvar.
+	capacity       atomic.Int64
+	waitingReaders atomic.Int64
+	waitingWriters atomic.Int64
+
+	consumeBuf []T  // Reused batch slice handed out by ConsumeBatch.
+	consuming  bool // True while a ConsumeBatch callback is running.
 
 	// Hook function that will be called before blocking on a read or hitting a deadline
 	// Returns true if the hook successfully handled the situation, false otherwise
@@ -44,6 +76,122 @@ type RingBuffer[T any] struct {
 	// Hook function that will be called before blocking on a write or hitting a deadline
 	// Returns true if the hook successfully handled the situation, false otherwise
 	preWriteBlockHook func() bool
+
+	// Hook called once, under the lock, when a gracefully closed buffer has been drained
+	onDrained func()
+	draining  bool // True between CloseGraceful and the read that empties the buffer
+
+	preserveOnClose bool // Close keeps queued items readable, like CloseGraceful
+
+	// Hook called with the operation name whenever a wait times out, see WithOnTimeout
+	onTimeout func(op string)
+
+	// Refills the buffer once a read leaves at most prefetchThreshold items, see WithPrefetch
+	prefetch          func() []T
+	prefetchThreshold int
+	prefetching       atomic.Bool // True while a prefetch is running
+
+	// Hook called once, under the lock, by the first Close
+	onClose func()
+	closed  bool // True once Close has run
+
+	// Hook called, under the lock, with a copy of the items dropped by Flush and FlushFast
+	onFlushItems func(items []T)
+
+	// Total size of the queued items as measured by sizer, see WithSizer
+	sizer      func(item T) int
+	bytes      int
+	byteBudget int // Byte total WriteBytesBounded keeps within, 0 for none
+
+	// Overwrite mode evicts the oldest items instead of blocking or failing when full
+	overwrite bool
+
+	// Coalescing Write replaces the newest item instead of blocking or failing when full
+	coalesce bool
+
+	// Chooses the items overwrite mode evicts, nil for the oldest
+	evictionPolicy EvictionPolicy[T]
+
+	// Keeps the items sorted instead of in write order, see NewPriority
+	less func(a, b T) bool
+
+	// Outstanding leases, oldest first; writes stop at the first one's slots. See LeaseN
+	leases []*Lease[T]
+
+	// Slots handed out by the last view read, zeroed by the next modification, see clearStale
+	staleAt, staleN int
+
+	// Unbounded mode grows the buffer instead of blocking or failing when full,
+	// and shrinks it back, down to minSize, as it empties. See NewUnbounded.
+	unbounded bool
+	minSize   int
+
+	// Slots only WritePriority may fill
+	reserved int
+
+	// Largest batch WriteMany and GetN move under one lock acquisition, 0 for no cap.
+	// Atomic since it is read before locking.
+	maxBatch atomic.Int64
+
+	// Serializes producers across the chunks of a batch, see WithContiguousBatches.
+	// The flag is atomic since it is read before locking.
+	writers           sync.Mutex
+	contiguousBatches atomic.Bool
+
+	// Sequence numbers, see GetOneSeq. The counters are atomic so PublishExpvar
+	// can read them without the lock, they are only written under it.
+	writeSeq    atomic.Uint64 // Items ever written, the newest one has this sequence number
+	reads       atomic.Uint64 // Items ever read
+	dropped     atomic.Uint64 // Items lost to overwrite
+	droppedSeen uint64        // Value of dropped at the last GetOneSeq
+	seqs        []uint64      // Sequence number stored per slot, only with WithSeqTracking
+
+	// Counters reported by Stats along with the ones above, which ResetStats can't
+	// clear since sequence numbers derive from them: it records baselines instead
+	writeWaits atomic.Uint64 // Times a writer started waiting for space
+	readWaits  atomic.Uint64 // Times a reader started waiting for items
+	timeouts   atomic.Uint64 // Waits that timed out
+	statsBase  atomic.Pointer[Stats]
+
+	// Hooks receiving items dropped by the buffer, called under the lock
+	onDiscard     func(item T)
+	onDiscardMany func(items []T)
+
+	// Queue of discard hook calls run by a dispatcher goroutine, see WithAsyncHooks
+	asyncHooks       *hookQueue
+	asyncHooksDrop   bool        // Drop calls instead of making writers wait once the queue is full
+	asyncHooksBehind atomic.Bool // True while calls wait past the queue, see waitAsyncHooks
+
+	// Buffer receiving a copy of every written item, and hook for its failures
+	tee        *RingBuffer[T]
+	onTeeError func(err error)
+
+	// Deep copies items handed out by the copying read paths
+	cloner func(item T) T
+
+	// Comparison used to drop writes equal to the last written item
+	dedupEq     func(a, b T) bool
+	dedupReport bool // Return ErrDuplicate instead of nil for dropped writes
+
+	// Zero every backing slot on clear and close, not only the queued ones
+	secureWipe bool
+
+	// GetRange returns the items available at its timeout with a nil error
+	quietRangeTimeout bool
+
+	verbose bool // Log diagnostics about errors, blocking and timeouts
+
+	clock Clock // Source of time for timeouts
+
+	latency []uint64 // Wait count per latency bucket, nil unless WithLatencyTracking
+
+	goroutines sync.WaitGroup // Background goroutines spawned by the buffer, see Wait
+
+	// Copy of the queued items for lock-free readers, see EnableSnapshotPublishing
+	snapshot      atomic.Pointer[[]T]
+	snapshotState snapshotState // Contents the current snapshot was taken from
+	snapshotTimer Timer         // Next publish, nil when not publishing
+	snapshotEpoch uint64        // Bumped whenever publishing stops, cancelling scheduled publishes
 }
 
 // New returns a new RingBuffer whose buffer has the given size.
@@ -52,10 +200,64 @@ func New[T any](size int) *RingBuffer[T] {
 		return nil
 	}
 
-	return &RingBuffer[T]{
-		buf:  make([]T, size),
-		size: size,
+	r := &RingBuffer[T]{
+		buf:   make([]T, size),
+		size:  size,
+		clock: realClock{},
+	}
+	r.capacity.Store(int64(size))
+
+	return r
+}
+
+// NewWithBuffer returns a new, empty RingBuffer that uses buf as its backing storage
+// instead of allocating one, e.g. an mmap'd region or an arena-allocated slice.
+// The capacity is len(buf), and whatever buf holds is treated as free space.
+// The caller owns the memory: it must stay valid, and must not be read or written
+// by anything else, while the buffer is in use. Grow moves the items to a new
+// Go heap slice and stops using buf.
+// Returns nil if buf is empty.
+func NewWithBuffer[T any](buf []T) *RingBuffer[T] {
+	if len(buf) == 0 {
+		return nil
+	}
+
+	r := &RingBuffer[T]{
+		buf:   buf[:len(buf):len(buf)],
+		size:  len(buf),
+		clock: realClock{},
 	}
+	r.capacity.Store(int64(len(buf)))
+
+	return r
+}
+
+// NewFromChannel returns a new RingBuffer of the given size seeded with the items
+// currently buffered in ch, to ease migrating from channels.
+// Behavior:
+// - Receives without blocking until ch is empty or closed, or the buffer is full,
+// so only items already available are moved, never future ones
+// - Items left in ch once the buffer is full stay in ch
+// - Returns nil if size <= 0, like New, without receiving anything
+func NewFromChannel[T any](ch <-chan T, size int) *RingBuffer[T] {
+	r := New[T](size)
+	if r == nil {
+		return nil
+	}
+
+	for !r.isFull {
+		select {
+		case item, ok := <-ch:
+			if !ok {
+				return r
+			}
+			r.Write(item)
+		default:
+			return r
+		}
+	}
+
+	return r
 }
 
 // NewWithConfig creates a new RingBuffer with the given size and configuration.
@@ -84,9 +286,44 @@ func NewWithConfig[T any](size int, cfg *config.RingBufferConfig[T]) (*RingBuffe
 		rb.WithPreWriteBlockHook(cfg.PreWriteBlockHook)
 	}
 
+	rb.WithOverwrite(cfg.Overwrite)
+
 	return rb, nil
 }
 
+// ApplyConfig updates the blocking mode, both timeouts and overwrite mode from cfg
+// under a single lock acquisition, so other goroutines never see a mix of old and new settings.
+// Behavior:
+// - Positive timeouts enable blocking mode, like WithReadTimeout and WithWriteTimeout
+// - Condition variables are only created when missing, so goroutines already waiting keep theirs
+// - Blocked readers and writers are woken to re-evaluate under the new settings, e.g. returning
+// ErrIsEmpty or ErrIsFull once blocking is disabled; a wait already started keeps its deadline
+// - The hooks of cfg are ignored, and a nil cfg changes nothing
+func (r *RingBuffer[T]) ApplyConfig(cfg *config.RingBufferConfig[T]) {
+	if cfg == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.block = cfg.Block || cfg.RTimeout > 0 || cfg.WTimeout > 0
+	if r.block && r.readCond == nil {
+		r.readCond = sync.NewCond(&r.mu)
+		r.writeCond = sync.NewCond(&r.mu)
+	}
+
+	// Readers wait on wTimeout and writers on rTimeout
+	r.wTimeout = cfg.RTimeout
+	r.rTimeout = cfg.WTimeout
+	r.overwrite = cfg.Overwrite
+
+	if r.readCond != nil {
+		r.readCond.Broadcast()
+		r.writeCond.Broadcast()
+	}
+}
+
 // WithBlocking sets the blocking mode of the ring buffer.
 // When blocking is enabled:
 // - Read operations will block when the buffer is empty
@@ -103,8 +340,25 @@ func (r *RingBuffer[T]) WithBlocking(block bool) *RingBuffer[T] {
 	return r
 }
 
+// WithRWMutex makes the methods that don't modify the buffer take a shared read lock,
+// so they run concurrently with each other, while every other method keeps taking the
+// exclusive lock. Useful for workloads dominated by peeks with occasional writes.
+// Shared: PeekOne, PeekN, PeekUpToN, PeekRecentN, PeekNView, PeekAllFunc, Inspect, String and Concat.
+// Behavior:
+// - Disabled by default: the exclusive side of a read-write lock is slower than a plain
+// mutex, so every write and read pays for it, see BenchmarkConcurrentPeek
+// - Swaps the lock itself, so it must be called before the buffer is shared between goroutines
+// - Works in blocking mode: the condition variables wait on the exclusive side, which
+// blocking reads and writes hold, and blocking peeks like PeekOneBlocking take it too
+// - The cloner, see WithCloner, runs concurrently for shared peeks and must be safe for concurrent use
+func (r *RingBuffer[T]) WithRWMutex(enabled bool) *RingBuffer[T] {
+	r.mu.setShared(enabled)
+	return r
+}
+
 // WithTimeout sets both read and write timeouts for the ring buffer.
-// When a timeout occurs, the operation returns context.DeadlineExceeded.
+// When a timeout occurs, reads return ErrReadTimeout and writes ErrWriteTimeout,
+// both wrapping context.DeadlineExceeded.
 // A timeout of 0 or less disables timeouts.
 // This method automatically enables blocking mode since timeouts require blocking behavior.
 func (r *RingBuffer[T]) WithTimeout(d time.Duration) *RingBuffer[T] {
@@ -147,6 +401,40 @@ func (r *RingBuffer[T]) WithWriteTimeout(d time.Duration) *RingBuffer[T] {
 	return r
 }
 
+// WithClock sets the source of time used by blocking operations to enforce their timeouts.
+// It defaults to the real clock; tests can inject a fake one to trigger timeouts
+// deterministically instead of sleeping. Passing nil restores the real clock.
+func (r *RingBuffer[T]) WithClock(clock Clock) *RingBuffer[T] {
+	if clock == nil {
+		clock = realClock{}
+	}
+
+	r.mu.Lock()
+	r.clock = clock
+	r.mu.Unlock()
+	return r
+}
+
+// WithMaxBlockedWriters caps how many writers can be blocked at the same time.
+// Once the cap is reached, a write that would block returns ErrTooManyWaiters
+// right away instead of joining the wait. A cap of 0 or less means unlimited.
+func (r *RingBuffer[T]) WithMaxBlockedWriters(n int) *RingBuffer[T] {
+	r.mu.Lock()
+	r.maxBlockedWriters = n
+	r.mu.Unlock()
+	return r
+}
+
+// WithMaxBlockedReaders caps how many readers can be blocked at the same time.
+// Once the cap is reached, a read that would block returns ErrTooManyWaiters
+// right away instead of joining the wait. A cap of 0 or less means unlimited.
+func (r *RingBuffer[T]) WithMaxBlockedReaders(n int) *RingBuffer[T] {
+	r.mu.Lock()
+	r.maxBlockedReaders = n
+	r.mu.Unlock()
+	return r
+}
+
 // WithPreReadBlockHook sets a hook function that will be called before blocking on a read
 // or hitting a deadline. This allows for custom handling of blocking situations,
 // such as trying alternative sources for data.
@@ -167,6 +455,314 @@ func (r *RingBuffer[T]) WithPreWriteBlockHook(hook func() bool) *RingBuffer[T] {
 	return r
 }
 
+// WithOnDrained sets a hook that fires exactly once when the last item is read out
+// of a buffer closed with CloseGraceful, giving a clean end of stream signal.
+// The hook runs under the lock right after the read that empties the buffer,
+// so it must not call back into the buffer.
+func (r *RingBuffer[T]) WithOnDrained(hook func()) *RingBuffer[T] {
+	r.mu.Lock()
+	r.onDrained = hook
+	r.mu.Unlock()
+	return r
+}
+
+// WithOverwrite enables or disables overwrite mode.
+// When overwrite is enabled, writes never block nor return ErrIsFull:
+// the oldest items are evicted to make room and handed to the discard hooks.
+func (r *RingBuffer[T]) WithOverwrite(overwrite bool) *RingBuffer[T] {
+	r.mu.Lock()
+	r.overwrite = overwrite
+	r.mu.Unlock()
+	return r
+}
+
+// WithCoalesce enables or disables coalescing writes, for "latest value wins" buffers
+// such as a size 1 buffer propagating config updates.
+// Behavior:
+// - When the buffer is full, Write replaces the newest queued item instead of blocking,
+// returning ErrIsFull or, in overwrite mode, evicting the oldest one
+// - The replaced item goes to the discard hooks, and the new one takes over its sequence number
+// - In a priority buffer, see NewPriority, the newest item is the lowest-priority one,
+// and the new item is moved to its sorted position
+// - Only Write coalesces, batch writes keep their usual behavior
+// - WriteBytesBounded coalesces only if item fits the byte budget in place of the
+// newest item, otherwise it goes on like a regular bounded write, see WithByteBudget
+func (r *RingBuffer[T]) WithCoalesce(coalesce bool) *RingBuffer[T] {
+	r.mu.Lock()
+	r.coalesce = coalesce
+	r.mu.Unlock()
+	return r
+}
+
+// WithEvictionPolicy sets the policy choosing which queued item overwrite mode evicts
+// to make room, instead of the oldest one. It is asked once per evicted item, and the
+// victim goes to the discard hooks like any evicted item.
+// Has no effect unless overwrite mode is enabled. Passing nil restores drop-oldest.
+// Evicting other than the oldest item leaves a hole in the sequence numbers of the
+// queued items, so GetOneSeq and ConsumeSeq need WithSeqTracking to stay exact.
+func (r *RingBuffer[T]) WithEvictionPolicy(policy EvictionPolicy[T]) *RingBuffer[T] {
+	r.mu.Lock()
+	r.evictionPolicy = policy
+	r.mu.Unlock()
+	return r
+}
+
+// WithSeqTracking stores the write sequence number of every item alongside it, costing
+// one uint64 per slot. Sequence numbers reported by GetOneSeq and ConsumeSeq are then the
+// ones assigned at write time, instead of being derived from the queue position, so they
+// reveal any reordering. Items already queued are numbered from their position.
+func (r *RingBuffer[T]) WithSeqTracking(enabled bool) *RingBuffer[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !enabled {
+		r.seqs = nil
+		return r
+	}
+
+	if r.seqs != nil {
+		return r
+	}
+
+	r.seqs = make([]uint64, r.size)
+	n := r.Length(true)
+	for i := range n {
+		r.seqs[(r.r+i)%r.size] = r.writeSeq.Load() - uint64(n-i) + 1
+	}
+	return r
+}
+
+// WithReservedCapacity reserves n slots of the buffer for WritePriority.
+// Every other write sees n fewer free slots, so it blocks, or returns ErrIsFull,
+// once the buffer holds size - n items, leaving room for priority producers.
+// Has no effect in overwrite mode. n is clamped to [0, size].
+func (r *RingBuffer[T]) WithReservedCapacity(n int) *RingBuffer[T] {
+	r.mu.Lock()
+	r.reserved = min(max(n, 0), r.size)
+	r.mu.Unlock()
+	return r
+}
+
+// WithMaxBatch caps how many items WriteMany and GetN move under a single lock
+// acquisition, bounding how long one large batch can hold the lock and starve
+// other readers and writers. Larger batches are split into chunks of n items,
+// and the lock is released between chunks.
+// With a cap set, WriteMany and GetN are no longer atomic for batches larger than n:
+// items of other writers may be interleaved, and a failing chunk leaves the previous
+// ones written or read. n <= 0 removes the cap.
+func (r *RingBuffer[T]) WithMaxBatch(n int) *RingBuffer[T] {
+	r.maxBatch.Store(int64(max(n, 0)))
+	return r
+}
+
+// WithContiguousBatches keeps the items of every batch write contiguous in read order,
+// never interleaved with items of other writers, even when the batch is written in
+// chunks: by WriteMany with WithMaxBatch, or by WriteManyProgress.
+// Without chunking, a WriteMany is always contiguous since it is written under one
+// lock acquisition, so this is only needed alongside them.
+// Behavior:
+// - Writers take a second, producer-only lock for the whole call, so a chunked batch
+// holds off other writers until its last chunk, while readers still run between chunks
+// - Writers are serialized even while one of them waits for space
+// - Hooks running during a write, like onProgress or the pre-write hook, must not
+// write to the buffer, it would deadlock
+func (r *RingBuffer[T]) WithContiguousBatches(enabled bool) *RingBuffer[T] {
+	r.contiguousBatches.Store(enabled)
+	return r
+}
+
+// WithOnDiscard sets a hook called for every item dropped by overwrite mode.
+// The hook runs under the lock, so it must not call back into the buffer.
+func (r *RingBuffer[T]) WithOnDiscard(hook func(item T)) *RingBuffer[T] {
+	r.mu.Lock()
+	r.onDiscard = hook
+	r.mu.Unlock()
+	return r
+}
+
+// WithOnDiscardMany sets a hook called with each batch of items dropped by overwrite mode.
+// A single eviction may be reported in two calls when it wraps around the buffer end.
+// The slice references the buffer and is only valid during the call.
+// The hook runs under the lock, so it must not call back into the buffer.
+func (r *RingBuffer[T]) WithOnDiscardMany(hook func(items []T)) *RingBuffer[T] {
+	r.mu.Lock()
+	r.onDiscardMany = hook
+	r.mu.Unlock()
+	return r
+}
+
+// WithAsyncHooks runs the discard hooks on a dedicated goroutine instead of under the
+// lock, so slow hooks doing I/O don't hold up readers and writers.
+// Behavior:
+// - Hook calls are queued and run in order
+// - The batch handed to the discard-many hook is a copy, safe to keep
+// - Hooks may lag behind the buffer: an item may be reported after it was evicted
+// - Once bufferSize calls are queued, the writes adding more wait for the dispatcher to
+// take them before returning, unless WithAsyncHooksDropOnFull drops them instead.
+// They wait after releasing the lock, so readers and the hooks aren't held up, and at
+// most one write per writer goroutine waits on top of the bufferSize queued calls
+// - The dispatcher goroutine runs the calls still queued and exits on Close, see Wait
+// - Calling it again while a dispatcher runs has no effect
+// Hooks run off the lock, so they may call back into the buffer, but not write to it
+// unless calls are dropped: once the queue is full, the write would wait for the hook itself.
+func (r *RingBuffer[T]) WithAsyncHooks(bufferSize int) *RingBuffer[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.asyncHooks != nil {
+		return r
+	}
+
+	q := &hookQueue{calls: make(chan func(), max(bufferSize, 0)), caughtUp: sync.NewCond(&r.mu)}
+	r.asyncHooks = q
+
+	r.goroutines.Add(1)
+	go func() {
+		defer r.goroutines.Done()
+		for call := range q.calls {
+			call()
+			r.runHookBacklog(q)
+		}
+		r.runHookBacklog(q)
+	}()
+
+	return r
+}
+
+// WithAsyncHooksDropOnFull makes async hooks drop calls when their queue is full,
+// instead of making the writes adding them wait, see WithAsyncHooks.
+func (r *RingBuffer[T]) WithAsyncHooksDropOnFull(drop bool) *RingBuffer[T] {
+	r.mu.Lock()
+	r.asyncHooksDrop = drop
+	r.mu.Unlock()
+	return r
+}
+
+// WithOnFlushItems sets a hook called by Flush and FlushFast with a copy of the items
+// queued right before they were dropped, in FIFO order, making Flush a persist-then-clear
+// operation. It isn't called when there was nothing to flush.
+// The hook runs under the lock, so it must not call back into the buffer,
+// and writes landing after the flush wait for it to return.
+func (r *RingBuffer[T]) WithOnFlushItems(hook func(items []T)) *RingBuffer[T] {
+	r.mu.Lock()
+	r.onFlushItems = hook
+	r.mu.Unlock()
+	return r
+}
+
+// WithOnCloseHook sets a hook fired exactly once by the first call to Close.
+// The hook runs under the lock, so it must not call back into the buffer.
+func (r *RingBuffer[T]) WithOnCloseHook(hook func()) *RingBuffer[T] {
+	r.mu.Lock()
+	r.onClose = hook
+	r.mu.Unlock()
+	return r
+}
+
+// WithPreserveOnClose makes Close keep already queued items readable instead of
+// clearing them. New writes are rejected with io.EOF and readers drain the
+// remaining items before getting io.EOF, exactly like CloseGraceful.
+func (r *RingBuffer[T]) WithPreserveOnClose(preserve bool) *RingBuffer[T] {
+	r.mu.Lock()
+	r.preserveOnClose = preserve
+	r.mu.Unlock()
+	return r
+}
+
+// WithTee duplicates every successful Write, WriteMany, WriteManyAt and WriteManyMulti
+// into the secondary buffer, e.g. to keep a rolling history in an overwrite mode buffer.
+// The copy happens after the primary write, outside its lock, and never blocks:
+// when the secondary is full (and not in overwrite mode) the items are dropped.
+// Failures on the secondary are swallowed, or reported to WithOnTeeError.
+// Passing nil disables the tee.
+func (r *RingBuffer[T]) WithTee(secondary *RingBuffer[T]) *RingBuffer[T] {
+	r.mu.Lock()
+	r.tee = secondary
+	r.mu.Unlock()
+	return r
+}
+
+// WithOnTeeError sets a hook called when copying written items into the tee buffer fails.
+func (r *RingBuffer[T]) WithOnTeeError(hook func(err error)) *RingBuffer[T] {
+	r.mu.Lock()
+	r.onTeeError = hook
+	r.mu.Unlock()
+	return r
+}
+
+// WithOnTimeout sets a hook called with the operation name, e.g. "Write" or "GetOne",
+// whenever a blocking operation times out, to track timeout rates without checking
+// every returned error. The hook runs on its own goroutine, so it doesn't delay the
+// operation returning and may call back into the buffer; Wait waits for pending calls.
+func (r *RingBuffer[T]) WithOnTimeout(hook func(op string)) *RingBuffer[T] {
+	r.mu.Lock()
+	r.onTimeout = hook
+	r.mu.Unlock()
+	return r
+}
+
+// WithCloner sets a function used to deep copy items returned by the copying
+// read paths (GetOne, GetN, PeekOne, PeekN, ConsumeBatch), so callers get independent
+// copies when the element type shares mutable state behind a pointer or interface.
+// View methods are zero-copy and never clone. Passing nil restores shallow copies.
+func (r *RingBuffer[T]) WithCloner(cloner func(item T) T) *RingBuffer[T] {
+	r.mu.Lock()
+	r.cloner = cloner
+	r.mu.Unlock()
+	return r
+}
+
+// WithDedup sets a comparison function used by Write to drop an item when it equals
+// the most recently written item that is still queued. Dropped writes return nil,
+// unless WithDedupReport(true) is set. Nothing is compared against when the buffer is empty.
+// Passing nil disables deduplication.
+func (r *RingBuffer[T]) WithDedup(eq func(a, b T) bool) *RingBuffer[T] {
+	r.mu.Lock()
+	r.dedupEq = eq
+	r.mu.Unlock()
+	return r
+}
+
+// WithDedupReport makes Write return ErrDuplicate for items dropped by WithDedup.
+func (r *RingBuffer[T]) WithDedupReport(report bool) *RingBuffer[T] {
+	r.mu.Lock()
+	r.dedupReport = report
+	r.mu.Unlock()
+	return r
+}
+
+// WithQuietRangeTimeout makes GetRange return the items available when it times out
+// with a nil error instead of ErrReadTimeout. It still returns ErrReadTimeout if none are.
+func (r *RingBuffer[T]) WithQuietRangeTimeout(quiet bool) *RingBuffer[T] {
+	r.mu.Lock()
+	r.quietRangeTimeout = quiet
+	r.mu.Unlock()
+	return r
+}
+
+// WithSecureWipe makes ClearBuffer, Close and FlushFast zero all backing slots instead
+// of only the queued ones, so no value (tokens, keys...) lingers in a slot reads didn't
+// zero, like those of outstanding leases. Reset and Flush always zero every slot.
+// Reads zero the slots they consume either way: right away when copying items out,
+// on the next modification for views, and on Release for leases.
+// When Close keeps queued items readable, only the slots not holding them are zeroed.
+func (r *RingBuffer[T]) WithSecureWipe(wipe bool) *RingBuffer[T] {
+	r.mu.Lock()
+	r.secureWipe = wipe
+	r.mu.Unlock()
+	return r
+}
+
+// WithVerbose enables logging of internal diagnostics (read errors, blocking,
+// timeouts, waiter caps, close) through the standard log package,
+// useful when debugging blocking or deadlock issues. Disabled by default.
+func (r *RingBuffer[T]) WithVerbose(verbose bool) *RingBuffer[T] {
+	r.mu.Lock()
+	r.verbose = verbose
+	r.mu.Unlock()
+	return r
+}
+
 // Length returns the number of items that can be read.
 // This is the actual number of items in the buffer.
 func (r *RingBuffer[T]) Length(lock bool) int {
@@ -175,10 +771,6 @@ func (r *RingBuffer[T]) Length(lock bool) int {
 		defer r.mu.Unlock()
 	}
 
-	if r.err == io.EOF {
-		return 0
-	}
-
 	if r.isFull {
 		return r.size
 	}
@@ -189,8 +781,30 @@ func (r *RingBuffer[T]) Length(lock bool) int {
 	return r.size - r.r + r.w
 }
 
+// Generation returns the current write generation of the buffer.
+// The generation is incremented every time items are written or slots are cleared,
+// and can be read without taking the lock.
+func (r *RingBuffer[T]) Generation() uint64 {
+	return r.generation.Load()
+}
+
+// ValidateGeneration reports whether no write happened since gen was observed.
+// Use it after working on a view to know whether its data might be stale.
+func (r *RingBuffer[T]) ValidateGeneration(gen uint64) bool {
+	return r.generation.Load() == gen
+}
+
 // Capacity returns the size of the underlying buffer
 func (r *RingBuffer[T]) Capacity() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.capacityLocked()
+}
+
+// capacityLocked returns the size of the underlying buffer, which resizing changes.
+// Must be called when locked.
+func (r *RingBuffer[T]) capacityLocked() int {
 	return r.size
 }
 
@@ -200,6 +814,12 @@ func (r *RingBuffer[T]) Free() int {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	return r.availableSpace()
+}
+
+// free returns the number of free slots.
+// Must be called when locked.
+func (r *RingBuffer[T]) free() int {
 	if r.isFull {
 		return 0
 	}
@@ -226,15 +846,48 @@ func (r *RingBuffer[T]) IsEmpty() bool {
 	return !r.isFull && r.w == r.r
 }
 
+// WouldBlockWrite reports whether writing n items would currently have to wait for space.
+// The reserved capacity counts as used, as it does for Write.
+// In non-blocking mode the same condition makes the write return ErrIsFull.
+// Always false in overwrite mode, since writes evict instead of waiting.
+// This is a point-in-time hint: the state may change right after it returns.
+func (r *RingBuffer[T]) WouldBlockWrite(n int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.overwrite && !r.leased() {
+		return false
+	}
+
+	return n > r.writeSpace(false)
+}
+
+// WouldBlockRead reports whether reading n items would currently have to wait for data.
+// In non-blocking mode the same condition makes the read return ErrIsEmpty.
+// This is a point-in-time hint: the state may change right after it returns.
+func (r *RingBuffer[T]) WouldBlockRead(n int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return n > r.Length(true)
+}
+
+// IsEmptyFast returns true when the ringbuffer looks empty, without taking the lock.
+// It may return a stale result; confirm with a real read.
+// Useful for spin-polling consumers that want to skip the lock when clearly empty.
+func (r *RingBuffer[T]) IsEmptyFast() bool {
+	return r.length.Load() == 0
+}
+
 // GetBlockedWriters returns the number of blocked writers
 func (r *RingBuffer[T]) GetBlockedWriters() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.err == io.EOF {
 		return 0
 	}
 
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	return r.blockedWriters
 }
 
@@ -252,15 +905,45 @@ func (r *RingBuffer[T]) CopyConfig(source *RingBuffer[T]) *RingBuffer[T] {
 	}
 
 	r.WithPreReadBlockHook(source.preReadBlockHook)
+	r.WithVerbose(source.verbose)
+	r.WithClock(source.clock)
 
 	return r
 }
 
 // ClearBuffer clears all items in the buffer and resets read/write positions.
 // Useful when shrinking the buffer or cleaning up resources.
+// Blocked readers and writers are not woken up, see SafeReset.
 func (r *RingBuffer[T]) ClearBuffer() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clearBufferLocked()
+}
+
+// SafeReset clears all items like ClearBuffer, then wakes every blocked reader and
+// writer so they re-evaluate against the empty buffer: writers waiting for space
+// go ahead, and readers go back to waiting.
+// Safe to call while operations are blocked. Error state and configuration are kept.
+func (r *RingBuffer[T]) SafeReset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clearBufferLocked()
+
+	if r.block {
+		r.readCond.Broadcast()
+		r.writeCond.Broadcast()
+	}
+}
+
+// clearBufferLocked implements ClearBuffer.
+// Must be called when locked.
+func (r *RingBuffer[T]) clearBufferLocked() {
 	var zero T
-	if r.w > r.r {
+	if r.secureWipe {
+		clear(r.buf)
+	} else if r.w > r.r {
 		for i := r.r; i < r.w; i++ {
 			r.buf[i] = zero
 		}
@@ -276,6 +959,9 @@ func (r *RingBuffer[T]) ClearBuffer() {
 	r.r = 0
 	r.w = 0
 	r.isFull = false
+	r.bytes = 0
+	r.dropLeases()
+	r.markModified()
 }
 
 // Close closes the ring buffer and cleans up resources.
@@ -283,24 +969,183 @@ func (r *RingBuffer[T]) ClearBuffer() {
 // - Sets error to io.EOF
 // - Clears all items in the buffer
 // - Signals all waiting readers and writers
+// - Fires the close hook
 // - All subsequent operations will return io.EOF
+// When WithPreserveOnClose(true) is set, queued items are kept readable like CloseGraceful.
+// Close is the final phase after Shutdown or CloseGraceful, and discards any item not yet read.
+// Close is idempotent: only the first call has any effect.
 func (r *RingBuffer[T]) Close() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.closeLocked(r.preserveOnClose)
+	return nil
+}
+
+// CloseWithDrain closes the buffer like Close, after handing a copy of the queued items,
+// in FIFO order, to sink, so none is lost on shutdown.
+// Behavior:
+// - sink runs under the lock while the buffer transitions to closed, so it must be quick,
+// e.g. hand the slice to another goroutine, and must not call back into the buffer
+// - sink isn't called when nothing is queued, or when the buffer is already closed
+// - The drained items are not readable afterwards, even with WithPreserveOnClose(true):
+// reads return io.EOF right away
+// - Waiting readers and writers are woken, and the close hook fires, as with Close
+func (r *RingBuffer[T]) CloseWithDrain(sink func(items []T)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	if n := r.Length(true); n > 0 && sink != nil {
+		part1, part2 := r.view(n)
+		sink(slices.Concat(part1, part2))
+	}
+
+	r.closeLocked(false)
+	return nil
+}
+
+// closeLocked implements Close, keeping queued items readable if preserve is set.
+// Must be called when locked.
+func (r *RingBuffer[T]) closeLocked(preserve bool) {
+	if r.closed {
+		return
+	}
+	r.closed = true
+	r.logVerbose("closed with %d queued items, preserve: %v", r.Length(true), preserve)
+
+	if preserve {
+		r.closeGraceful()
+		if r.secureWipe {
+			r.wipeFree()
+		}
+	} else {
+		r.setErr(io.EOF, true)
+		r.draining = false
+		r.clearBufferLocked()
+
+		if r.block {
+			r.readCond.Broadcast()
+			r.writeCond.Broadcast()
+		}
+	}
+
+	if r.onClose != nil {
+		r.onClose()
+	}
+	r.stopSnapshots()
+
+	// Let the dispatcher run what is queued and exit
+	if r.asyncHooks != nil {
+		close(r.asyncHooks.calls)
+		r.asyncHooks = nil
+	}
+}
+
+// Shutdown is the first phase of a two-phase close. It marks the buffer as
+// draining: writes are rejected with io.EOF while readers drain queued items,
+// exactly like CloseGraceful. A later Close finalizes the teardown and fires the close hook.
+func (r *RingBuffer[T]) Shutdown() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.closeGraceful()
+}
+
+// Wait blocks until every background goroutine spawned by the buffer has exited,
+// so that after Close and Wait nothing references the buffer anymore, which
+// leak-checked tests rely on. The goroutines spawned are:
+// - The two goroutines of each Batches call, which exit once the buffer is closed
+// and drained or stop fires, and their last batch has been received
+// - The async hooks dispatcher started by WithAsyncHooks, which exits on Close
+func (r *RingBuffer[T]) Wait() {
+	r.goroutines.Wait()
+}
+
+// CloseGraceful closes the ring buffer while letting readers drain queued items.
+// Behavior:
+// - Sets error to io.EOF, so all subsequent writes return io.EOF
+// - Items already queued remain readable
+// - Reads return io.EOF once the buffer is empty
+// - Fires the drained hook once the last item is read, or right away if already empty
+// - Signals all waiting readers and writers
+// Calling Close afterwards discards any item not yet read.
+func (r *RingBuffer[T]) CloseGraceful() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.closeGraceful()
+}
+
+// closeGraceful implements CloseGraceful.
+// Must be called when locked.
+func (r *RingBuffer[T]) closeGraceful() error {
 	if r.err == io.EOF {
 		return nil
 	}
 
 	r.setErr(io.EOF, true)
-	r.ClearBuffer()
+	r.draining = true
+	r.afterRead(0)
 
-	if r.block {
-		r.readCond.Broadcast()
-		r.writeCond.Broadcast()
+	return nil
+}
+
+// CloseGracefulTimeout closes the buffer like CloseGraceful, but gives readers at most d
+// to drain it, so a stalled reader can't hold up shutdown: once d elapses with items
+// still queued, the buffer is closed like Close and those items are discarded.
+// Behavior:
+// - Waits for the buffer to drain whatever the blocking mode; a d of 0 or less doesn't wait
+// - Discarded items are passed to the discard hooks, see WithOnDiscard
+// - Waiting readers and writers are woken, and the close hook fires, as with Close
+// - Returns right away with a nil error if the buffer is already closed
+// Returns:
+// - ErrDrainTimeout if items were still queued when d elapsed
+func (r *RingBuffer[T]) CloseGracefulTimeout(d time.Duration) error {
+	if r == nil {
+		return errors.ErrNilBuffer
 	}
 
-	return nil
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	r.closeGraceful()
+
+	if d > 0 && r.Length(true) > 0 {
+		if r.stateCond == nil {
+			r.stateCond = sync.NewCond(&r.mu)
+		}
+		r.stateWaiters++
+		deadline := r.clock.Now().Add(d)
+
+		// A concurrent Close clears the buffer, which ends the wait too
+		for r.Length(true) > 0 {
+			if !r.waitCondUntil(&r.stateTimer, &r.stateCond, deadline) {
+				break
+			}
+		}
+
+		r.stateWaiters--
+	}
+
+	var err error
+	if n := r.Length(true); n > 0 {
+		r.logVerbose("drain timed out, discarding %d queued items", n)
+		part1, part2 := r.view(n)
+		r.discard(part1)
+		r.discard(part2)
+		err = errors.ErrDrainTimeout
+	}
+
+	r.closeLocked(false)
+	return err
 }
 
 // Reset resets the buffer to its initial state.
@@ -320,7 +1165,12 @@ func (r *RingBuffer[T]) Reset() {
 	r.r = 0
 	r.w = 0
 	r.isFull = false
+	r.bytes = 0
 	r.err = nil
+	r.draining = false
+	r.closed = false
+	r.dropLeases()
+	r.markModified()
 }
 
 // Flush clears all items from the buffer while maintaining its configuration.
@@ -333,6 +1183,8 @@ func (r *RingBuffer[T]) Flush() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.notifyFlush()
+
 	var zero T
 	for i := range r.buf {
 		r.buf[i] = zero
@@ -340,6 +1192,175 @@ func (r *RingBuffer[T]) Flush() {
 	r.r = 0
 	r.w = 0
 	r.isFull = false
+	r.bytes = 0
+	r.dropLeases()
+	r.markModified()
+}
+
+// notifyFlush passes a copy of the queued items to the flush hook, see WithOnFlushItems.
+// Must be called when locked, before the items are dropped.
+func (r *RingBuffer[T]) notifyFlush() {
+	n := r.Length(true)
+	if r.onFlushItems == nil || n == 0 {
+		return
+	}
+
+	part1, part2 := r.view(n)
+	r.onFlushItems(slices.Concat(part1, part2))
+}
+
+// FlushFast drops all items from the buffer by resetting the read and write
+// positions, without zeroing the underlying slots.
+// Use it for value element types (int, structs without pointers...) where zeroing
+// is wasted work. For pointer-like element types use Flush or ClearBuffer instead,
+// otherwise the stale slots keep the referenced objects from being garbage collected.
+// Signals all waiting writers since the whole buffer is free again.
+func (r *RingBuffer[T]) FlushFast() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.notifyFlush()
+
+	if r.secureWipe {
+		clear(r.buf)
+	}
+	r.r = 0
+	r.w = 0
+	r.isFull = false
+	r.bytes = 0
+	r.dropLeases()
+	r.markModified()
+
+	if r.block {
+		r.readCond.Broadcast()
+	}
+}
+
+// Grow enlarges the buffer by additional slots, keeping the queued items in FIFO order.
+// Behavior:
+// - Reallocates the backing array and moves the queued items to its start
+// - Outstanding views (GetNView, PeekNView, GetAllView...) keep pointing at the old
+// array, so they no longer reflect the buffer: drop them before calling Grow
+// - Bumps the generation, so ValidateGeneration reports views taken before as stale
+// - Wakes all blocked writers, since there is more free space
+// - Returns ErrInvalidLength if additional <= 0
+func (r *RingBuffer[T]) Grow(additional int) error {
+	if r == nil {
+		return errors.ErrNilBuffer
+	}
+
+	if additional <= 0 {
+		return errors.ErrInvalidLength
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.initialized() {
+		return errors.ErrNotInitialized
+	}
+
+	r.resize(r.size+additional, r.secureWipe)
+
+	if r.block {
+		r.readCond.Broadcast()
+	}
+
+	return nil
+}
+
+// Resize changes the size of the buffer to newSize, growing or shrinking it while
+// keeping the queued items in FIFO order, so throughput changes don't require a new buffer.
+// Behavior:
+// - Reallocates the backing array and moves the queued items to its start, like Grow
+// - Outstanding views and leases are left on the old array, see Grow and LeaseN
+// - Wakes all blocked readers and writers, so they re-evaluate the free space
+// - A batch write or read already waiting for more items than newSize keeps waiting
+// until it times out or the buffer is closed
+// - Doesn't reallocate if newSize is the current size
+// Returns:
+// - ErrInvalidLength, leaving the buffer unchanged, if newSize is smaller than the number
+// of queued items, the reserved capacity or the minimum size of an unbounded buffer
+func (r *RingBuffer[T]) Resize(newSize int) error {
+	if r == nil {
+		return errors.ErrNilBuffer
+	}
+
+	if newSize <= 0 {
+		return errors.ErrInvalidLength
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.initialized() {
+		return errors.ErrNotInitialized
+	}
+
+	if newSize < r.Length(true) || newSize < r.reserved || r.unbounded && newSize < r.minSize {
+		return errors.ErrInvalidLength
+	}
+
+	if newSize == r.size {
+		return nil
+	}
+
+	r.resize(newSize, r.secureWipe)
+
+	if r.block {
+		r.readCond.Broadcast()
+		r.writeCond.Broadcast()
+	}
+
+	return nil
+}
+
+// resize moves the queued items to the start of a new backing array of the given size,
+// which must hold them all. The old array is zeroed if wipe is set.
+// Must be called when locked.
+func (r *RingBuffer[T]) resize(size int, wipe bool) {
+	n := r.Length(true)
+	buf := make([]T, size)
+	part1, part2 := r.view(n)
+	copy(buf, part1)
+	copy(buf[len(part1):], part2)
+
+	if wipe {
+		clear(r.buf)
+	}
+
+	if r.seqs != nil {
+		seqs := make([]uint64, len(buf))
+		for i := range n {
+			seqs[i] = r.seqs[(r.r+i)%r.size]
+		}
+		r.seqs = seqs
+	}
+
+	r.buf = buf
+	r.size = len(buf)
+	r.staleN = 0
+	r.capacity.Store(int64(r.size))
+	r.r = 0
+	r.w = n % r.size
+	r.isFull = n == r.size
+	r.dropLeases()
+	r.markModified()
+}
+
+// wipeFree zeroes the slots not holding queued items.
+// Must be called when locked.
+func (r *RingBuffer[T]) wipeFree() {
+	if r.isFull {
+		return
+	}
+
+	if r.w >= r.r {
+		clear(r.buf[r.w:])
+		clear(r.buf[:r.r])
+	} else {
+		clear(r.buf[r.w:r.r])
+	}
 }
 
 // GetBlockedReaders returns the number of blocked readers
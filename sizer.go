@@ -0,0 +1,128 @@
+package ringbuffer
+
+import (
+	"context"
+
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+)
+
+// WithSizer makes the buffer track the total size of its queued items, as measured
+// by sizer, e.g. the length of []byte messages, see LengthBytes.
+// Behavior:
+// - The total is updated as items are written, read, evicted or cleared
+// - sizer runs under the lock on every write and read, so it must be cheap and must
+// not call back into the buffer
+// - sizer must return the same size for an item as long as it is queued, so items
+// must not be resized in place
+// - The items already queued are measured when the sizer is set
+// - Passing a nil sizer stops the tracking
+func (r *RingBuffer[T]) WithSizer(sizer func(item T) int) *RingBuffer[T] {
+	r.mu.Lock()
+	r.sizer = sizer
+	r.bytes = 0
+	if n := r.Length(true); n > 0 && sizer != nil {
+		part1, part2 := r.view(n)
+		r.bytes = r.sizeOf(part1) + r.sizeOf(part2)
+	}
+	r.mu.Unlock()
+	return r
+}
+
+// WithByteBudget sets the total size of queued items, as measured by the sizer set
+// with WithSizer, that WriteBytesBounded keeps within. Other writes ignore it.
+// A budget of 0 or less removes it.
+func (r *RingBuffer[T]) WithByteBudget(budget int) *RingBuffer[T] {
+	r.mu.Lock()
+	r.byteBudget = max(budget, 0)
+	// Writers waiting for bytes must see the new budget
+	if r.block {
+		r.readCond.Broadcast()
+	}
+	r.mu.Unlock()
+	return r
+}
+
+// WriteBytesBounded writes a single item like Write, but also keeps the total size of
+// the queued items within the byte budget, turning the buffer into a byte-bounded queue.
+// Behavior:
+// - Accepts item only if LengthBytes plus its size is within the budget, and a slot is free
+// - An empty buffer always accepts an item within the budget
+// - Otherwise blocks in blocking mode, returns ErrIsFull if not blocking, or evicts the
+// oldest items until item fits when overwrite is enabled
+// - Behaves like Write without a sizer or a byte budget, see WithSizer and WithByteBudget
+// - Returns ErrTooMuchDataToWrite if item alone is larger than the budget
+// - Returns ErrWriteTimeout if timeout occurs
+func (r *RingBuffer[T]) WriteBytesBounded(item T) error {
+	return r.write(context.Background(), item, false, true)
+}
+
+// LengthBytes returns the total size of the queued items, as measured by the sizer
+// set with WithSizer, or 0 without one.
+func (r *RingBuffer[T]) LengthBytes() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.bytes
+}
+
+// FreeBytes returns how many more bytes WriteBytesBounded accepts before blocking.
+// Returns -1 without a byte budget, see WithByteBudget.
+func (r *RingBuffer[T]) FreeBytes() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.byteBudget == 0 {
+		return -1
+	}
+	return max(r.byteBudget-r.bytes, 0)
+}
+
+// sizeOf returns the total size of items, or 0 without a sizer.
+func (r *RingBuffer[T]) sizeOf(items []T) int {
+	if r.sizer == nil {
+		return 0
+	}
+
+	total := 0
+	for _, item := range items {
+		total += r.sizer(item)
+	}
+	return total
+}
+
+// sizeOne returns the size of item, or 0 without a sizer.
+func (r *RingBuffer[T]) sizeOne(item T) int {
+	if r.sizer == nil {
+		return 0
+	}
+	return r.sizer(item)
+}
+
+// unsize takes the n items about to be read from the byte total.
+// Must be called when locked, before advancing the read position.
+func (r *RingBuffer[T]) unsize(n int) {
+	if r.sizer == nil || n == 0 {
+		return
+	}
+
+	part1, part2 := r.view(n)
+	r.bytes -= r.sizeOf(part1) + r.sizeOf(part2)
+}
+
+// checkBudget returns the size of item for a bounded write, see WriteBytesBounded.
+// Returns ErrTooMuchDataToWrite if item could never fit the byte budget.
+// Must be called when locked.
+func (r *RingBuffer[T]) checkBudget(item T) (int, error) {
+	size := r.sizeOne(item)
+	if r.byteBudget > 0 && size > r.byteBudget {
+		return 0, errors.ErrTooMuchDataToWrite
+	}
+	return size, nil
+}
+
+// overBudget reports whether size more bytes would exceed the byte budget.
+// An empty buffer takes any item within the budget, whatever the byte total.
+// Must be called when locked.
+func (r *RingBuffer[T]) overBudget(size int) bool {
+	return r.byteBudget > 0 && r.bytes+size > r.byteBudget && r.Length(true) > 0
+}
@@ -0,0 +1,85 @@
+package ringbuffer
+
+import "time"
+
+// snapshotState identifies the buffer contents a snapshot was taken from.
+type snapshotState struct {
+	generation uint64
+	r, length  int
+}
+
+// EnableSnapshotPublishing starts publishing a copy of the queued items every interval,
+// for lock-free readers such as a UI rendering the buffer each frame, see LatestSnapshot.
+// Behavior:
+// - Publishes a snapshot right away, then every interval on the buffer's clock
+// - A snapshot is copied under the lock into a new slice, items passed through the cloner,
+// and is skipped when nothing was written or read since the previous one
+// - LatestSnapshot lags the buffer by at most interval, plus the time to take the lock and copy
+// - Calling it again replaces the interval, and an interval <= 0 stops publishing,
+// keeping the last snapshot
+// - Publishing stops once the buffer is closed, LatestSnapshot keeps returning the last snapshot
+func (r *RingBuffer[T]) EnableSnapshotPublishing(interval time.Duration) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stopSnapshots()
+	if interval <= 0 || r.closed {
+		return
+	}
+
+	r.publishSnapshot(r.snapshotEpoch, interval)
+}
+
+// LatestSnapshot returns the items queued when the last snapshot was published,
+// in read order, with a single atomic load: no lock and no allocation.
+// The slice is shared by every caller and must not be modified.
+// Returns nil if EnableSnapshotPublishing was never called.
+func (r *RingBuffer[T]) LatestSnapshot() []T {
+	if r == nil {
+		return nil
+	}
+
+	if items := r.snapshot.Load(); items != nil {
+		return *items
+	}
+	return nil
+}
+
+// publishSnapshot publishes a copy of the queued items if they changed, and schedules
+// the next publish. epoch is the value of snapshotEpoch the publishing was started
+// with, a scheduled publish of a stopped or restarted one does nothing.
+// Must be called when locked.
+func (r *RingBuffer[T]) publishSnapshot(epoch uint64, interval time.Duration) {
+	if epoch != r.snapshotEpoch || r.closed {
+		return
+	}
+
+	state := snapshotState{r.generation.Load(), r.r, r.Length(true)}
+	if r.snapshot.Load() == nil || state != r.snapshotState {
+		items := make([]T, state.length)
+		r.copyOut(items)
+		r.snapshot.Store(&items)
+		r.snapshotState = state
+	}
+
+	r.snapshotTimer = r.clock.AfterFunc(interval, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.publishSnapshot(epoch, interval)
+	})
+}
+
+// stopSnapshots stops the scheduled publish, if any.
+// Must be called when locked.
+func (r *RingBuffer[T]) stopSnapshots() {
+	r.snapshotEpoch++
+	if r.snapshotTimer != nil {
+		r.snapshotTimer.Stop()
+		r.snapshotTimer = nil
+	}
+}
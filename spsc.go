@@ -0,0 +1,104 @@
+package ringbuffer
+
+import (
+	"sync/atomic"
+
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+)
+
+// cacheLineSize is the assumed size of a CPU cache line.
+const cacheLineSize = 64
+
+// cacheLinePad keeps the fields around it on separate cache lines.
+type cacheLinePad struct{ _ [cacheLineSize]byte }
+
+// SPSC is a lock-free ring buffer for exactly one producer goroutine and one
+// consumer goroutine. It never blocks: TryWrite and TryRead fail fast instead.
+//
+// The read and write positions are padded onto separate cache lines, so the
+// producer updating tail and the consumer updating head don't invalidate each
+// other's cache line (false sharing). Each side also keeps a cached copy of the
+// other side's position, on its own line, to avoid reading the shared one on
+// every operation.
+type SPSC[T any] struct {
+	_    cacheLinePad
+	head atomic.Uint64 // next position to read, only written by the consumer
+	_    cacheLinePad
+	tail atomic.Uint64 // next position to write, only written by the producer
+	_    cacheLinePad
+
+	cachedHead uint64 // producer's last seen head
+	_          cacheLinePad
+	cachedTail uint64 // consumer's last seen tail
+	_          cacheLinePad
+
+	buf  []T
+	mask uint64
+}
+
+// NewSPSC returns a new SPSC ring whose capacity is size rounded up to the next power of two.
+// Returns nil if size is less than or equal to 0.
+func NewSPSC[T any](size int) *SPSC[T] {
+	if size <= 0 {
+		return nil
+	}
+
+	capacity := 1
+	for capacity < size {
+		capacity <<= 1
+	}
+
+	return &SPSC[T]{
+		buf:  make([]T, capacity),
+		mask: uint64(capacity - 1),
+	}
+}
+
+// TryWrite writes a single item to the ring.
+// Must only be called from the producer goroutine.
+// Returns ErrIsFull if the ring is full.
+func (s *SPSC[T]) TryWrite(item T) error {
+	tail := s.tail.Load()
+	if tail-s.cachedHead == uint64(len(s.buf)) {
+		s.cachedHead = s.head.Load()
+		if tail-s.cachedHead == uint64(len(s.buf)) {
+			return errors.ErrIsFull
+		}
+	}
+
+	s.buf[tail&s.mask] = item
+	s.tail.Store(tail + 1)
+
+	return nil
+}
+
+// TryRead reads a single item from the ring.
+// Must only be called from the consumer goroutine.
+// Returns ErrIsEmpty if the ring is empty.
+func (s *SPSC[T]) TryRead() (item T, err error) {
+	head := s.head.Load()
+	if head == s.cachedTail {
+		s.cachedTail = s.tail.Load()
+		if head == s.cachedTail {
+			return item, errors.ErrIsEmpty
+		}
+	}
+
+	var zero T
+	item = s.buf[head&s.mask]
+	s.buf[head&s.mask] = zero
+	s.head.Store(head + 1)
+
+	return item, nil
+}
+
+// Length returns the number of items that can be read.
+// The result may be stale by the time it is used.
+func (s *SPSC[T]) Length() int {
+	return int(s.tail.Load() - s.head.Load())
+}
+
+// Capacity returns the size of the underlying buffer.
+func (s *SPSC[T]) Capacity() int {
+	return len(s.buf)
+}
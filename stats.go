@@ -0,0 +1,75 @@
+package ringbuffer
+
+// Stats holds the cumulative counters of a buffer, see Stats.
+type Stats struct {
+	Writes        uint64 // Items written, including the ones later lost to overwrite
+	Reads         uint64 // Items read
+	Dropped       uint64 // Items lost to overwrite
+	BlockedWrites uint64 // Times a writer started waiting for space
+	BlockedReads  uint64 // Times a reader started waiting for items
+	Timeouts      uint64 // Waits that ended with ErrReadTimeout or ErrWriteTimeout
+	Length        int    // Items queued when the stats were taken
+}
+
+// Stats returns the counters accumulated since the buffer was created, or since
+// the last ResetStats call.
+// Behavior:
+// - Reads atomic counters, so it never takes the buffer lock or slows down readers and writers
+// - The counters are read one by one, they may be slightly out of sync with each other
+// - A writer or reader woken up without making progress waits again, and is counted again
+func (r *RingBuffer[T]) Stats() Stats {
+	if r == nil {
+		return Stats{}
+	}
+
+	stats := Stats{
+		Writes:        r.writeSeq.Load(),
+		Reads:         r.reads.Load(),
+		Dropped:       r.dropped.Load(),
+		BlockedWrites: r.writeWaits.Load(),
+		BlockedReads:  r.readWaits.Load(),
+		Timeouts:      r.timeouts.Load(),
+		Length:        int(r.length.Load()),
+	}
+
+	if base := r.statsBase.Load(); base != nil {
+		stats.Writes = since(stats.Writes, base.Writes)
+		stats.Reads = since(stats.Reads, base.Reads)
+		stats.Dropped = since(stats.Dropped, base.Dropped)
+		stats.BlockedWrites = since(stats.BlockedWrites, base.BlockedWrites)
+		stats.BlockedReads = since(stats.BlockedReads, base.BlockedReads)
+		stats.Timeouts = since(stats.Timeouts, base.Timeouts)
+	}
+
+	return stats
+}
+
+// ResetStats restarts the counters reported by Stats from zero, to take stats per
+// interval. The queued items and their sequence numbers are not affected.
+func (r *RingBuffer[T]) ResetStats() {
+	if r == nil {
+		return
+	}
+
+	// The lock keeps writes and reads from landing between two counters
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.statsBase.Store(&Stats{
+		Writes:        r.writeSeq.Load(),
+		Reads:         r.reads.Load(),
+		Dropped:       r.dropped.Load(),
+		BlockedWrites: r.writeWaits.Load(),
+		BlockedReads:  r.readWaits.Load(),
+		Timeouts:      r.timeouts.Load(),
+	})
+}
+
+// since returns the increase of a counter from base, 0 if a concurrent ResetStats
+// moved base past the value read.
+func since(value, base uint64) uint64 {
+	if value < base {
+		return 0
+	}
+	return value - base
+}
@@ -1,7 +1,10 @@
 package test
 
 import (
+	"io"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/AlexsanderHamir/ringbuffer"
 	"github.com/AlexsanderHamir/ringbuffer/errors"
@@ -102,3 +105,302 @@ func TestRingBufferEdgeCases(t *testing.T) {
 	rb = ringbuffer.New[*TestValue](10)
 	require.NotNil(t, rb)
 }
+
+func TestRingBufferDedup(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithDedup(func(a, b int) bool { return a == b })
+	require.NotNil(t, rb)
+
+	for _, v := range []int{1, 1, 2, 2, 2, 1} {
+		require.NoError(t, rb.Write(v))
+	}
+	assert.Equal(t, 3, rb.Length(false))
+
+	items, err := rb.GetN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 1}, items)
+
+	// Nothing to compare against once the buffer is drained
+	require.NoError(t, rb.Write(1))
+	assert.Equal(t, 1, rb.Length(false))
+
+	rb.WithDedupReport(true)
+	assert.ErrorIs(t, rb.Write(1), errors.ErrDuplicate)
+	assert.Equal(t, 1, rb.Length(false))
+}
+
+func TestRingBufferIsEmptyFast(t *testing.T) {
+	rb := ringbuffer.New[int](3)
+	require.NotNil(t, rb)
+	assert.True(t, rb.IsEmptyFast())
+
+	require.NoError(t, rb.Write(1))
+	assert.False(t, rb.IsEmptyFast())
+
+	_, err := rb.GetOne()
+	require.NoError(t, err)
+	assert.True(t, rb.IsEmptyFast())
+
+	_, err = rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+	assert.False(t, rb.IsEmptyFast())
+
+	_, _, err = rb.GetAllView()
+	require.NoError(t, err)
+	assert.True(t, rb.IsEmptyFast())
+
+	require.NoError(t, rb.Write(1))
+	rb.Flush()
+	assert.True(t, rb.IsEmptyFast())
+}
+
+func TestRingBufferWouldBlock(t *testing.T) {
+	rb := ringbuffer.New[int](3)
+	require.NotNil(t, rb)
+
+	assert.True(t, rb.WouldBlockRead(1))
+	assert.False(t, rb.WouldBlockWrite(3))
+	assert.True(t, rb.WouldBlockWrite(4))
+
+	_, err := rb.WriteMany([]int{1, 2})
+	require.NoError(t, err)
+
+	assert.False(t, rb.WouldBlockRead(2))
+	assert.True(t, rb.WouldBlockRead(3))
+	assert.False(t, rb.WouldBlockWrite(1))
+	assert.True(t, rb.WouldBlockWrite(2))
+
+	rb.WithOverwrite(true)
+	assert.False(t, rb.WouldBlockWrite(2))
+}
+
+func TestRingBufferFlushFast(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- rb.Write(5)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	rb.FlushFast()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("blocked writer should have been woken by FlushFast")
+	}
+
+	item, err := rb.GetOne()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, item)
+	assert.True(t, rb.IsEmpty())
+}
+
+func TestNewWithBuffer(t *testing.T) {
+	assert.Nil(t, ringbuffer.NewWithBuffer[int](nil))
+	assert.Nil(t, ringbuffer.NewWithBuffer(make([]int, 0, 8)))
+
+	// Existing contents are free space, and the extra capacity of buf is never used
+	backing := []int{9, 9, 9, 9, 9}
+	rb := ringbuffer.NewWithBuffer(backing[:4])
+	require.NotNil(t, rb)
+	assert.Equal(t, 4, rb.Capacity())
+	assert.Equal(t, 0, rb.Length(false))
+
+	_, err := rb.WriteMany([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+	assert.ErrorIs(t, rb.Write(5), errors.ErrIsFull)
+
+	// Items are stored in the caller's memory
+	assert.Equal(t, []int{1, 2, 3, 4, 9}, backing)
+
+	items, err := rb.GetN(4)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, items)
+
+	// Grow stops using the caller's memory
+	require.NoError(t, rb.Write(6))
+	before := slices.Clone(backing)
+	require.NoError(t, rb.Grow(2))
+	_, err = rb.WriteMany([]int{7, 8})
+	require.NoError(t, err)
+	assert.Equal(t, before, backing)
+
+	items, err = rb.GetN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{6, 7, 8}, items)
+}
+
+func TestNewFromChannel(t *testing.T) {
+	ch := make(chan int, 10)
+	assert.Nil(t, ringbuffer.NewFromChannel(ch, 0))
+
+	for i := range 5 {
+		ch <- i
+	}
+
+	// Takes what fits, the rest stays in the channel
+	rb := ringbuffer.NewFromChannel(ch, 3)
+	require.NotNil(t, rb)
+	assert.True(t, rb.IsFull())
+	items, err := rb.GetN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, items)
+	assert.Len(t, ch, 2)
+
+	// Stops once the channel is empty, without waiting for more
+	rb = ringbuffer.NewFromChannel(ch, 8)
+	require.NotNil(t, rb)
+	assert.Equal(t, 2, rb.Length(false))
+	require.NoError(t, rb.Write(5))
+	items, err = rb.GetN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, items)
+
+	// And once it is closed
+	ch <- 6
+	close(ch)
+	rb = ringbuffer.NewFromChannel(ch, 8)
+	require.NotNil(t, rb)
+	item, err := rb.GetOne()
+	require.NoError(t, err)
+	assert.Equal(t, 6, item)
+	assert.True(t, rb.IsEmpty())
+}
+
+func TestNewTypedConstructors(t *testing.T) {
+	assert.Nil(t, ringbuffer.NewBytes(0))
+	assert.Nil(t, ringbuffer.NewInts(-1))
+
+	bytes := ringbuffer.NewBytes(8)
+	require.NotNil(t, bytes)
+	n, err := bytes.WriteMany([]byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	p := make([]byte, 8)
+	n, err = bytes.Read(p)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(p[:n]))
+
+	ints := ringbuffer.NewInts(2)
+	require.NotNil(t, ints)
+	require.NoError(t, ints.Write(7))
+	item, err := ints.GetOne()
+	require.NoError(t, err)
+	assert.Equal(t, 7, item)
+	assert.Equal(t, 2, ints.Capacity())
+}
+
+func TestRingBufferOnFlushItems(t *testing.T) {
+	var flushed [][]int
+	rb := ringbuffer.New[int](4).WithOnFlushItems(func(items []int) {
+		flushed = append(flushed, items)
+	})
+	require.NotNil(t, rb)
+
+	// Nothing to flush
+	rb.Flush()
+	assert.Empty(t, flushed)
+
+	// Wrapped contents are handed over in FIFO order
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+	_, err = rb.WriteMany([]int{4, 5, 6})
+	require.NoError(t, err)
+	rb.Flush()
+	assert.Equal(t, [][]int{{3, 4, 5, 6}}, flushed)
+	assert.True(t, rb.IsEmpty())
+
+	// The hook gets a copy, later writes don't change it
+	_, err = rb.WriteMany([]int{7, 8})
+	require.NoError(t, err)
+	rb.FlushFast()
+	require.NoError(t, rb.Write(9))
+	assert.Equal(t, [][]int{{3, 4, 5, 6}, {7, 8}}, flushed)
+}
+
+func TestRingBufferUnread(t *testing.T) {
+	const size = 4
+	for rot := range size {
+		rb := ringbuffer.New[int](size).WithSeqTracking(true)
+		require.NotNil(t, rb)
+
+		// Move the read position to rot, then queue 1, 2, 3
+		for i := range rot {
+			require.NoError(t, rb.Write(-i))
+			_, err := rb.GetOne()
+			require.NoError(t, err)
+		}
+		_, err := rb.WriteMany([]int{1, 2, 3})
+		require.NoError(t, err)
+
+		item, err := rb.GetOne()
+		require.NoError(t, err)
+		require.Equal(t, 1, item)
+
+		require.NoError(t, rb.Unread(item), "rot %d", rot)
+		require.NoError(t, rb.CheckInvariants(), "rot %d", rot)
+
+		item, seq, _, err := rb.GetOneSeq()
+		require.NoError(t, err)
+		assert.Equal(t, 1, item, "rot %d", rot)
+		assert.Equal(t, uint64(rot+1), seq, "rot %d", rot)
+
+		// Pushing back two items fills the buffer
+		require.NoError(t, rb.Unread(item))
+		require.NoError(t, rb.Unread(0))
+		assert.True(t, rb.IsFull(), "rot %d", rot)
+		assert.ErrorIs(t, rb.Unread(-1), errors.ErrIsFull)
+
+		items, err := rb.GetN(size)
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 2, 3}, items, "rot %d", rot)
+	}
+}
+
+func TestRingBufferUnreadEmptyAndClosed(t *testing.T) {
+	rb := ringbuffer.New[int](2).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	done := make(chan int, 1)
+	go func() {
+		item, err := rb.GetOne()
+		assert.NoError(t, err)
+		done <- item
+	}()
+
+	require.NoError(t, rb.Unread(7))
+	assert.Equal(t, 7, <-done)
+
+	require.NoError(t, rb.Close())
+	assert.ErrorIs(t, rb.Unread(1), io.EOF)
+
+	// A draining buffer rejects it like a write, leaving the queued items as they were
+	for _, closeGraceful := range []func(rb *ringbuffer.RingBuffer[int]) error{
+		(*ringbuffer.RingBuffer[int]).CloseGraceful,
+		(*ringbuffer.RingBuffer[int]).Shutdown,
+	} {
+		rb := ringbuffer.New[int](2)
+		require.NotNil(t, rb)
+		require.NoError(t, rb.Write(1))
+		require.NoError(t, closeGraceful(rb))
+
+		assert.ErrorIs(t, rb.Unread(0), io.EOF)
+		item, err := rb.GetOne()
+		require.NoError(t, err)
+		assert.Equal(t, 1, item)
+		_, err = rb.GetOne()
+		assert.ErrorIs(t, err, io.EOF)
+	}
+
+	var zero ringbuffer.RingBuffer[int]
+	assert.ErrorIs(t, zero.Unread(1), errors.ErrNotInitialized)
+}
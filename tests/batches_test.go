@@ -0,0 +1,154 @@
+package test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatches(t *testing.T) {
+	t.Run("Delivers every item in order", func(t *testing.T) {
+		rb := ringbuffer.New[int](16).WithBlocking(true).WithTimeout(5 * time.Second)
+		require.NotNil(t, rb)
+
+		const total = 250
+		go func() {
+			for i := range total {
+				assert.NoError(t, rb.Write(i))
+			}
+			assert.NoError(t, rb.CloseGraceful())
+		}()
+
+		var got []int
+		for batch := range rb.Batches(10, nil) {
+			assert.NotEmpty(t, batch)
+			assert.LessOrEqual(t, len(batch), 10)
+			got = append(got, batch...)
+		}
+
+		require.Len(t, got, total)
+		for i, item := range got {
+			assert.Equal(t, i, item)
+		}
+	})
+
+	t.Run("Stop ends a waiting loop", func(t *testing.T) {
+		rb := ringbuffer.New[int](4).WithBlocking(true)
+		require.NotNil(t, rb)
+
+		baseline := runtime.NumGoroutine()
+
+		stop := make(chan struct{})
+		batches := rb.Batches(4, stop)
+
+		require.NoError(t, rb.Write(1))
+		assert.Equal(t, []int{1}, <-batches)
+
+		require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 1 }, time.Second, time.Millisecond)
+		close(stop)
+
+		select {
+		case _, ok := <-batches:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("channel should be closed once stop fires")
+		}
+
+		// Both goroutines are gone, polled by hand like in TestTimeoutsDontLeakGoroutines
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.LessOrEqual(t, runtime.NumGoroutine(), baseline)
+	})
+
+	t.Run("Close ends a waiting loop", func(t *testing.T) {
+		rb := ringbuffer.New[int](4).WithBlocking(true)
+		require.NotNil(t, rb)
+
+		batches := rb.Batches(4, nil)
+		require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 1 }, time.Second, time.Millisecond)
+		require.NoError(t, rb.Close())
+
+		select {
+		case _, ok := <-batches:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("channel should be closed once the buffer closes")
+		}
+	})
+
+	t.Run("Non-blocking delivers queued items", func(t *testing.T) {
+		rb := ringbuffer.New[int](8)
+		require.NotNil(t, rb)
+
+		_, err := rb.WriteMany([]int{1, 2, 3, 4, 5})
+		require.NoError(t, err)
+
+		var got [][]int
+		for batch := range rb.Batches(2, nil) {
+			got = append(got, batch)
+		}
+		assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, got)
+	})
+
+	t.Run("Invalid batch size", func(t *testing.T) {
+		rb := ringbuffer.New[int](4)
+		require.NotNil(t, rb)
+
+		_, ok := <-rb.Batches(0, nil)
+		assert.False(t, ok)
+	})
+}
+
+func TestWaitForBackgroundGoroutines(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	rb := ringbuffer.New[int](8).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	// Nothing spawned yet
+	rb.Wait()
+
+	stop := make(chan struct{})
+	first := rb.Batches(2, stop)
+	second := rb.Batches(2, nil)
+	assert.Greater(t, runtime.NumGoroutine(), baseline)
+
+	waited := make(chan struct{})
+	go func() {
+		rb.Wait()
+		close(waited)
+	}()
+
+	close(stop)
+	for range first {
+	}
+
+	select {
+	case <-waited:
+		t.Fatal("Wait returned while a Batches loop is still running")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	require.NoError(t, rb.Close())
+	for range second {
+	}
+
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatal("Wait should return once every Batches loop has exited")
+	}
+
+	// Done runs as the very last step of each goroutine, give them a moment to finish exiting
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), baseline)
+}
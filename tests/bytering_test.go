@@ -0,0 +1,153 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByteRing(t *testing.T) {
+	data := strings.Repeat("the quick brown fox jumps over the lazy dog\n", 100)
+
+	t.Run("io.Copy in", func(t *testing.T) {
+		ring := ringbuffer.NewByteRing(16)
+		require.NotNil(t, ring)
+
+		go func() {
+			_, err := io.Copy(ring, strings.NewReader(data))
+			assert.NoError(t, err)
+			assert.NoError(t, ring.Close())
+		}()
+
+		got, err := io.ReadAll(ring)
+		require.NoError(t, err)
+		assert.Equal(t, data, string(got))
+	})
+
+	t.Run("io.Copy out", func(t *testing.T) {
+		ring := ringbuffer.NewByteRing(64)
+		require.NotNil(t, ring)
+
+		go func() {
+			for _, line := range strings.SplitAfter(data, "\n") {
+				_, err := ring.Write([]byte(line))
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, ring.Close())
+		}()
+
+		var out bytes.Buffer
+		n, err := io.Copy(&out, ring)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), n)
+		assert.Equal(t, data, out.String())
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		ring := ringbuffer.NewByteRing(8)
+		require.NotNil(t, ring)
+
+		n, err := ring.Write([]byte("abc"))
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+		require.NoError(t, ring.Close())
+
+		_, err = ring.Write([]byte("d"))
+		assert.ErrorIs(t, err, io.ErrClosedPipe)
+
+		// Bytes written before Close are still readable
+		buf := make([]byte, 8)
+		n, err = ring.Read(buf)
+		require.NoError(t, err)
+		assert.Equal(t, "abc", string(buf[:n]))
+
+		n, err = ring.Read(buf)
+		assert.ErrorIs(t, err, io.EOF)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("Close wakes a blocked writer", func(t *testing.T) {
+		ring := ringbuffer.NewByteRing(2)
+		require.NotNil(t, ring)
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := ring.Write([]byte("abcd"))
+			done <- err
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, ring.Close())
+
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, io.ErrClosedPipe)
+		case <-time.After(time.Second):
+			t.Fatal("Close should wake the blocked writer")
+		}
+	})
+
+	assert.Nil(t, ringbuffer.NewByteRing(0))
+}
+
+func TestByteRingFromTimeouts(t *testing.T) {
+	rb := ringbuffer.NewBytes(4).WithTimeout(10 * time.Millisecond)
+	ring := ringbuffer.NewByteRingFrom(rb)
+	require.NotNil(t, ring)
+
+	p := make([]byte, 8)
+	_, err := ring.Read(p)
+	assert.ErrorIs(t, err, errors.ErrReadTimeout)
+
+	// Nobody reads, so only what fits is written
+	n, err := ring.Write([]byte("abcdefgh"))
+	assert.ErrorIs(t, err, errors.ErrWriteTimeout)
+	assert.Equal(t, 4, n)
+
+	n, err = ring.Read(p)
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", string(p[:n]))
+	assert.Nil(t, ringbuffer.NewByteRingFrom(nil))
+}
+
+func TestByteRingFromNonBlocking(t *testing.T) {
+	ring := ringbuffer.NewByteRingFrom(ringbuffer.NewBytes(4))
+	require.NotNil(t, ring)
+
+	_, err := ring.Read(make([]byte, 4))
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+
+	n, err := ring.Write([]byte("abcdef"))
+	assert.ErrorIs(t, err, errors.ErrIsFull)
+	assert.Equal(t, 4, n)
+}
+
+func TestByteRingGzip(t *testing.T) {
+	data := strings.Repeat("compressible payload ", 500)
+	ring := ringbuffer.NewByteRing(64)
+	require.NotNil(t, ring)
+
+	// Compress into the ring on one side, decompress out of it on the other
+	go func() {
+		zw := gzip.NewWriter(ring)
+		_, err := io.Copy(zw, strings.NewReader(data))
+		assert.NoError(t, err)
+		assert.NoError(t, zw.Close())
+		assert.NoError(t, ring.Close())
+	}()
+
+	zr, err := gzip.NewReader(bufio.NewReader(ring))
+	require.NoError(t, err)
+	out, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, data, string(out))
+}
@@ -0,0 +1,207 @@
+package test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced ringbuffer.Clock.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock   *fakeClock
+	when    time.Time
+	f       func()
+	stopped bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) ringbuffer.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, when: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance moves the clock forward and runs the timers that became due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	var due []func()
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		switch {
+		case t.stopped:
+		case !t.when.After(c.now):
+			due = append(due, t.f)
+		default:
+			pending = append(pending, t)
+		}
+	}
+	c.timers = pending
+	c.mu.Unlock()
+
+	for _, f := range due {
+		f()
+	}
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	wasPending := !t.stopped
+	t.stopped = true
+	return wasPending
+}
+
+// gapClock is a fakeClock that, the first time Now is called while a timer is pending,
+// fires the pending timers and then reports the time from before they fired. This
+// reproduces a timer firing right after a waiter checked its deadline, before it
+// started waiting, which the waiter must not miss.
+type gapClock struct {
+	*fakeClock
+	fired atomic.Bool
+}
+
+func newGapClock() *gapClock {
+	return &gapClock{fakeClock: newFakeClock()}
+}
+
+func (c *gapClock) Now() time.Time {
+	now := c.fakeClock.Now()
+
+	c.mu.Lock()
+	pending := len(c.timers) > 0
+	c.mu.Unlock()
+	if !pending || !c.fired.CompareAndSwap(false, true) {
+		return now
+	}
+
+	// The timer callback may need the lock the caller holds, so don't wait for it forever
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Advance(time.Hour)
+	}()
+	select {
+	case <-done:
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	return now
+}
+
+func TestWithClockReadTimeout(t *testing.T) {
+	clock := newFakeClock()
+	rb := ringbuffer.New[int](2).WithTimeout(time.Hour).WithClock(clock)
+	require.NotNil(t, rb)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := rb.GetOne()
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 1 }, time.Second, time.Millisecond)
+
+	// Not due yet
+	clock.Advance(59 * time.Minute)
+	select {
+	case err := <-done:
+		t.Fatalf("GetOne returned before its timeout: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Advance(time.Minute)
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, errors.ErrReadTimeout)
+	case <-time.After(time.Second):
+		t.Fatal("GetOne should time out once the clock reaches the deadline")
+	}
+}
+
+func TestWithClockWriteTimeout(t *testing.T) {
+	clock := newFakeClock()
+	rb := ringbuffer.New[int](1).WithTimeout(time.Minute).WithClock(clock)
+	require.NotNil(t, rb)
+	require.NoError(t, rb.Write(1))
+
+	done := make(chan error, 1)
+	go func() { done <- rb.Write(2) }()
+	require.Eventually(t, func() bool { return rb.GetBlockedWriters() == 1 }, time.Second, time.Millisecond)
+
+	clock.Advance(time.Minute)
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, errors.ErrWriteTimeout)
+	case <-time.After(time.Second):
+		t.Fatal("Write should time out once the clock reaches the deadline")
+	}
+}
+
+func TestWithClockStaggeredTimeouts(t *testing.T) {
+	clock := newFakeClock()
+	rb := ringbuffer.New[int](2).WithTimeout(time.Hour).WithClock(clock)
+	require.NotNil(t, rb)
+
+	// The readers share one timer, armed for the earliest deadline
+	first := make(chan error, 1)
+	go func() {
+		_, err := rb.GetOne()
+		first <- err
+	}()
+	require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 1 }, time.Second, time.Millisecond)
+
+	clock.Advance(30 * time.Minute)
+	second := make(chan error, 1)
+	go func() {
+		_, err := rb.GetOne()
+		second <- err
+	}()
+	require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 2 }, time.Second, time.Millisecond)
+
+	clock.Advance(30 * time.Minute)
+	select {
+	case err := <-first:
+		assert.ErrorIs(t, err, errors.ErrReadTimeout)
+	case <-time.After(time.Second):
+		t.Fatal("the first GetOne should time out at its deadline")
+	}
+
+	// Woken too, the second reader waits again for its own deadline
+	require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 1 }, time.Second, time.Millisecond)
+	select {
+	case err := <-second:
+		t.Fatalf("the second GetOne returned before its timeout: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Advance(30 * time.Minute)
+	select {
+	case err := <-second:
+		assert.ErrorIs(t, err, errors.ErrReadTimeout)
+	case <-time.After(time.Second):
+		t.Fatal("the second GetOne should time out at its deadline")
+	}
+}
@@ -0,0 +1,383 @@
+package test
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseGracefulDrain(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	drained := 0
+	rb.WithOnDrained(func() { drained++ })
+
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+
+	require.NoError(t, rb.CloseGraceful())
+	assert.ErrorIs(t, rb.Write(4), io.EOF)
+
+	for i := 1; i <= 3; i++ {
+		assert.Equal(t, 0, drained)
+		item, err := rb.GetOne()
+		assert.NoError(t, err)
+		assert.Equal(t, i, item)
+	}
+	assert.Equal(t, 1, drained)
+
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, io.EOF)
+
+	// Neither a second close nor further reads fire the hook again
+	require.NoError(t, rb.CloseGraceful())
+	require.NoError(t, rb.Close())
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 1, drained)
+}
+
+func TestCloseGracefulEmpty(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	drained := 0
+	rb.WithOnDrained(func() { drained++ })
+
+	done := make(chan error)
+	go func() {
+		_, err := rb.GetOne()
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, rb.CloseGraceful())
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, io.EOF)
+	case <-time.After(time.Second):
+		t.Fatal("blocked reader should have been released")
+	}
+	assert.Equal(t, 1, drained)
+}
+
+func TestCloseAfterCloseGraceful(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+
+	require.NoError(t, rb.CloseGraceful())
+	require.NoError(t, rb.Close())
+
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 0, rb.Length(false))
+}
+
+func TestClosePreservesQueuedItems(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithBlocking(true).WithPreserveOnClose(true)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2})
+	require.NoError(t, err)
+
+	require.NoError(t, rb.Close())
+	assert.ErrorIs(t, rb.Write(3), io.EOF)
+
+	items, err := rb.GetN(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, items)
+
+	// Readers are released as soon as the queue is drained
+	done := make(chan error)
+	go func() {
+		_, err := rb.GetOne()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, io.EOF)
+	case <-time.After(time.Second):
+		t.Fatal("GetOne should return io.EOF on a drained closed buffer")
+	}
+}
+
+func TestShutdownThenClose(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	var events []string
+	rb.WithOnDrained(func() { events = append(events, "drained") })
+	rb.WithOnCloseHook(func() { events = append(events, "closed") })
+
+	_, err := rb.WriteMany([]int{1, 2})
+	require.NoError(t, err)
+
+	require.NoError(t, rb.Shutdown())
+	assert.ErrorIs(t, rb.Write(3), io.EOF)
+	assert.Empty(t, events)
+
+	items, err := rb.GetN(2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, items)
+	assert.Equal(t, []string{"drained"}, events)
+
+	require.NoError(t, rb.Close())
+	require.NoError(t, rb.Close())
+	assert.Equal(t, []string{"drained", "closed"}, events)
+
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestCloseWithoutShutdown(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	closed := 0
+	rb.WithOnCloseHook(func() { closed++ })
+
+	_, err := rb.WriteMany([]int{1, 2})
+	require.NoError(t, err)
+
+	require.NoError(t, rb.Close())
+	require.NoError(t, rb.Close())
+	assert.Equal(t, 1, closed)
+
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestConcurrentClose(t *testing.T) {
+	const closers = 32
+
+	rb := ringbuffer.New[int](2).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	var closed atomic.Int32
+	rb.WithOnCloseHook(func() { closed.Add(1) })
+
+	// Blocked reader woken by the close
+	readErr := make(chan error, 1)
+	go func() {
+		_, err := rb.GetOne()
+		readErr <- err
+	}()
+	require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 1 }, time.Second, time.Millisecond)
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for range closers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			assert.NoError(t, rb.Close())
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), closed.Load())
+	select {
+	case err := <-readErr:
+		assert.ErrorIs(t, err, io.EOF)
+	case <-time.After(time.Second):
+		t.Fatal("Close should wake the blocked reader")
+	}
+}
+
+func TestGetNDrainsClosedBuffer(t *testing.T) {
+	for _, blocking := range []bool{false, true} {
+		rb := ringbuffer.New[int](5).WithBlocking(blocking)
+		require.NotNil(t, rb)
+
+		_, err := rb.WriteMany([]int{1, 2, 3})
+		require.NoError(t, err)
+		require.NoError(t, rb.CloseGraceful())
+
+		items, err := rb.GetN(3)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, items)
+
+		_, err = rb.GetN(1)
+		assert.ErrorIs(t, err, io.EOF)
+		_, err = rb.GetOne()
+		assert.ErrorIs(t, err, io.EOF)
+	}
+}
+
+func TestGetNShortOnClosedBuffer(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2})
+	require.NoError(t, err)
+
+	// A reader waiting for more items than will ever arrive is released by the close
+	done := make(chan error, 1)
+	go func() {
+		_, err := rb.GetN(3)
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 1 }, time.Second, time.Millisecond)
+	require.NoError(t, rb.CloseGraceful())
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	case <-time.After(time.Second):
+		t.Fatal("GetN should be released by the close")
+	}
+
+	_, _, err = rb.GetNView(3)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// The queued items are still there
+	items, err := rb.GetN(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, items)
+
+	_, err = rb.GetN(1)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestRingBufferCloseWithDrain(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true).WithPreserveOnClose(true)
+	require.NotNil(t, rb)
+
+	// Wrapped contents reach the sink in FIFO order
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+	_, err = rb.WriteMany([]int{4, 5, 6})
+	require.NoError(t, err)
+
+	var drained [][]int
+	sink := func(items []int) { drained = append(drained, items) }
+	require.NoError(t, rb.CloseWithDrain(sink))
+	assert.Equal(t, [][]int{{3, 4, 5, 6}}, drained)
+
+	// Closed and emptied, even though Close would have preserved the items
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.ErrorIs(t, rb.Write(7), io.EOF)
+
+	require.NoError(t, rb.CloseWithDrain(sink))
+	assert.Len(t, drained, 1)
+}
+
+func TestRingBufferCloseWithDrainWakesReaders(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := rb.GetOne()
+		done <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	called := false
+	require.NoError(t, rb.CloseWithDrain(func([]int) { called = true }))
+	assert.False(t, called)
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, io.EOF)
+	case <-time.After(time.Second):
+		t.Fatal("CloseWithDrain should wake blocked readers")
+	}
+}
+
+func TestRingBufferCloseGracefulTimeoutStalledReader(t *testing.T) {
+	var discarded []int
+	rb := ringbuffer.New[int](5).WithBlocking(true).WithOnDiscard(func(item int) {
+		discarded = append(discarded, item)
+	})
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+
+	// Nobody reads, so the drain can only time out
+	start := time.Now()
+	err = rb.CloseGracefulTimeout(50 * time.Millisecond)
+	assert.ErrorIs(t, err, errors.ErrDrainTimeout)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, []int{1, 2, 3}, discarded)
+
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.ErrorIs(t, rb.Write(4), io.EOF)
+
+	// Already closed
+	assert.NoError(t, rb.CloseGracefulTimeout(time.Second))
+}
+
+func TestRingBufferCloseGracefulTimeoutDrained(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+
+	var got []int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			item, err := rb.GetOne()
+			if err != nil {
+				return
+			}
+			got = append(got, item)
+		}
+	}()
+
+	assert.NoError(t, rb.CloseGracefulTimeout(5*time.Second))
+	<-done
+	assert.Equal(t, []int{1, 2, 3}, got)
+}
+
+func TestRingBufferCloseGracefulTimeoutNoWait(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	require.NoError(t, rb.Write(1))
+	assert.ErrorIs(t, rb.CloseGracefulTimeout(0), errors.ErrDrainTimeout)
+	assert.True(t, rb.IsEmpty())
+
+	empty := ringbuffer.New[int](5)
+	assert.NoError(t, empty.CloseGracefulTimeout(0))
+}
+
+func TestRingBufferCloseGracefulTimeoutFiresBeforeWait(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithBlocking(true).WithClock(newGapClock())
+	require.NotNil(t, rb)
+	require.NoError(t, rb.Write(1))
+
+	// The timeout fires between the deadline check and the wait
+	done := make(chan error, 1)
+	go func() { done <- rb.CloseGracefulTimeout(time.Minute) }()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, errors.ErrDrainTimeout)
+	case <-time.After(5 * time.Second):
+		t.Fatal("CloseGracefulTimeout missed its timeout")
+	}
+}
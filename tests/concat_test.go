@@ -0,0 +1,44 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcat(t *testing.T) {
+	rb := ringbuffer.New[[]byte](3)
+	require.NotNil(t, rb)
+
+	assert.Nil(t, ringbuffer.Concat(rb))
+	assert.Nil(t, ringbuffer.ConcatDrain(rb))
+
+	// Queued frames wrapping around the buffer end
+	_, err := rb.WriteMany([][]byte{[]byte("xx"), []byte("he"), []byte("llo")})
+	require.NoError(t, err)
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+	require.NoError(t, rb.Write([]byte(", world")))
+
+	assert.Equal(t, []byte("hello, world"), ringbuffer.Concat(rb))
+	assert.Equal(t, 3, rb.Length(false), "Concat doesn't consume")
+
+	// The result doesn't alias the queued frames
+	out := ringbuffer.Concat(rb)
+	out[0] = 'H'
+	assert.Equal(t, []byte("hello, world"), ringbuffer.Concat(rb))
+
+	assert.Equal(t, []byte("hello, world"), ringbuffer.ConcatDrain(rb))
+	assert.True(t, rb.IsEmpty())
+	assert.Nil(t, ringbuffer.ConcatDrain(rb))
+
+	// Named byte slice types work too
+	type frame []byte
+	frames := ringbuffer.New[frame](2)
+	require.NotNil(t, frames)
+	_, err = frames.WriteMany([]frame{frame("ab"), frame("cd")})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("abcd"), ringbuffer.ConcatDrain(frames))
+}
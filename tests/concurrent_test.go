@@ -1,8 +1,9 @@
 package test
 
 import (
-	"context"
+	stderrors "errors"
 	"fmt"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
@@ -163,7 +164,7 @@ func TestConcurrentReadTimeout(t *testing.T) {
 				go func() {
 					defer wg.Done()
 					_, err := rb.GetOne()
-					if err != context.DeadlineExceeded {
+					if !stderrors.Is(err, errors.ErrReadTimeout) {
 						errorsChan <- err
 					}
 				}()
@@ -201,7 +202,7 @@ func TestConcurrentWriteTimeout(t *testing.T) {
 					defer wg.Done()
 					item := writerID
 					err := rb.Write(item)
-					if err != context.DeadlineExceeded {
+					if !stderrors.Is(err, errors.ErrWriteTimeout) {
 						errorsChan <- err
 					}
 				}(i)
@@ -216,3 +217,486 @@ func TestConcurrentWriteTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestMaxBlockedWaiters(t *testing.T) {
+	t.Run("Writers", func(t *testing.T) {
+		rb := ringbuffer.New[int](1).WithBlocking(true).WithMaxBlockedWriters(2)
+		require.NotNil(t, rb)
+		require.NoError(t, rb.Write(0))
+
+		var wg sync.WaitGroup
+		for i := range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rb.Write(i + 1)
+			}()
+		}
+
+		require.Eventually(t, func() bool { return rb.GetBlockedWriters() == 2 }, time.Second, time.Millisecond)
+
+		err := rb.Write(3)
+		assert.ErrorIs(t, err, errors.ErrTooManyWaiters)
+
+		rb.Close()
+		wg.Wait()
+	})
+
+	t.Run("Readers", func(t *testing.T) {
+		rb := ringbuffer.New[int](1).WithBlocking(true).WithMaxBlockedReaders(2)
+		require.NotNil(t, rb)
+
+		var wg sync.WaitGroup
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rb.GetOne()
+			}()
+		}
+
+		require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 2 }, time.Second, time.Millisecond)
+
+		_, err := rb.GetOne()
+		assert.ErrorIs(t, err, errors.ErrTooManyWaiters)
+
+		_, err = rb.GetN(1)
+		assert.ErrorIs(t, err, errors.ErrTooManyWaiters)
+
+		rb.Close()
+		wg.Wait()
+	})
+}
+
+func TestMixedBulkAndSingleReaders(t *testing.T) {
+	rb := ringbuffer.New[int](10).WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+
+	waitBlocked := func(n int) {
+		require.Eventually(t, func() bool {
+			return rb.GetBlockedReaders() == n
+		}, time.Second, time.Millisecond)
+	}
+
+	// The bulk reader parks first, so a plain Signal would wake it and not the single reader
+	bulk := make(chan []int, 1)
+	go func() {
+		items, err := rb.GetN(5)
+		assert.NoError(t, err)
+		bulk <- items
+	}()
+	waitBlocked(1)
+
+	single := make(chan int, 1)
+	go func() {
+		item, err := rb.GetOne()
+		assert.NoError(t, err)
+		single <- item
+	}()
+	waitBlocked(2)
+
+	require.NoError(t, rb.Write(1))
+	select {
+	case item := <-single:
+		assert.Equal(t, 1, item)
+	case <-time.After(time.Second):
+		t.Fatal("GetOne should have been woken by the write")
+	}
+
+	for i := 2; i <= 6; i++ {
+		require.NoError(t, rb.Write(i))
+	}
+	select {
+	case items := <-bulk:
+		assert.Equal(t, []int{2, 3, 4, 5, 6}, items)
+	case <-time.After(time.Second):
+		t.Fatal("GetN should have been woken once enough items were written")
+	}
+}
+
+func TestWriteManyWakesAllReaders(t *testing.T) {
+	const readers = 8
+
+	rb := ringbuffer.New[int](readers).WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+
+	results := make(chan int, readers)
+	for range readers {
+		go func() {
+			item, err := rb.GetOne()
+			assert.NoError(t, err)
+			results <- item
+		}()
+	}
+	require.Eventually(t, func() bool {
+		return rb.GetBlockedReaders() == readers
+	}, time.Second, time.Millisecond)
+
+	items := make([]int, readers)
+	for i := range items {
+		items[i] = i
+	}
+	_, err := rb.WriteMany(items)
+	require.NoError(t, err)
+
+	got := make([]int, 0, readers)
+	for range readers {
+		select {
+		case item := <-results:
+			got = append(got, item)
+		case <-time.After(time.Second):
+			t.Fatalf("only %d of %d readers got an item from a single WriteMany", len(got), readers)
+		}
+	}
+	assert.ElementsMatch(t, items, got)
+}
+
+func TestTimeoutsDontLeakGoroutines(t *testing.T) {
+	rb := ringbuffer.New[int](1).WithTimeout(time.Millisecond)
+	require.NotNil(t, rb)
+
+	baseline := runtime.NumGoroutine()
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				_, err := rb.GetOne()
+				assert.ErrorIs(t, err, errors.ErrReadTimeout)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, rb.Write(1))
+	for range 20 {
+		assert.ErrorIs(t, rb.Write(2), errors.ErrWriteTimeout)
+	}
+
+	// Timed out waits leave no goroutine behind. Polled by hand, since
+	// assert.Eventually runs its condition in a goroutine of its own.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), baseline)
+}
+
+func TestReadTimeoutSurvivesWakeups(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+
+	reads := map[string]func(rb *ringbuffer.RingBuffer[int]) error{
+		"GetOne": func(rb *ringbuffer.RingBuffer[int]) error {
+			_, err := rb.GetOne()
+			return err
+		},
+		"GetOneSeq": func(rb *ringbuffer.RingBuffer[int]) error {
+			_, _, _, err := rb.GetOneSeq()
+			return err
+		},
+		"GetN": func(rb *ringbuffer.RingBuffer[int]) error {
+			_, err := rb.GetN(2)
+			return err
+		},
+		"Read": func(rb *ringbuffer.RingBuffer[int]) error {
+			_, err := rb.Read(make([]int, 2))
+			return err
+		},
+		"GetAllView": func(rb *ringbuffer.RingBuffer[int]) error {
+			_, _, err := rb.GetAllView()
+			return err
+		},
+		"GetNView": func(rb *ringbuffer.RingBuffer[int]) error {
+			_, _, err := rb.GetNView(2)
+			return err
+		},
+		"GetUpToNView": func(rb *ringbuffer.RingBuffer[int]) error {
+			_, _, err := rb.GetUpToNView(2)
+			return err
+		},
+		"ConsumeBatch": func(rb *ringbuffer.RingBuffer[int]) error {
+			return rb.ConsumeBatch(2, func([]int) error { return nil })
+		},
+	}
+
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			rb := ringbuffer.New[int](2).WithReadTimeout(timeout)
+			require.NotNil(t, rb)
+
+			// Keep waking the reader without giving it anything to read
+			stop := make(chan struct{})
+			defer close(stop)
+			go func() {
+				for {
+					select {
+					case <-stop:
+						return
+					case <-time.After(10 * time.Millisecond):
+						rb.WakeUpOneReader()
+					}
+				}
+			}()
+
+			start := time.Now()
+			err := read(rb)
+			elapsed := time.Since(start)
+
+			assert.ErrorIs(t, err, errors.ErrReadTimeout)
+			assert.GreaterOrEqual(t, elapsed, timeout)
+			assert.Less(t, elapsed, 2*timeout)
+		})
+	}
+}
+
+func TestWriteTimeoutSurvivesWakeups(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+
+	writes := map[string]func(rb *ringbuffer.RingBuffer[int]) error{
+		"Write": func(rb *ringbuffer.RingBuffer[int]) error {
+			return rb.Write(3)
+		},
+		"WriteMany": func(rb *ringbuffer.RingBuffer[int]) error {
+			_, err := rb.WriteMany([]int{3})
+			return err
+		},
+		"WriteManyMulti": func(rb *ringbuffer.RingBuffer[int]) error {
+			_, err := rb.WriteManyMulti([]int{3}, []int{4})
+			return err
+		},
+		// Waits for the lease to be released instead of evicting
+		"WriteMany leased": func(rb *ringbuffer.RingBuffer[int]) error {
+			rb.WithOverwrite(true)
+			lease, err := rb.LeaseN(2)
+			if err != nil {
+				return err
+			}
+			defer lease.Release()
+
+			_, err = rb.WriteMany([]int{3})
+			return err
+		},
+	}
+
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			rb := ringbuffer.New[int](2).WithWriteTimeout(timeout)
+			require.NotNil(t, rb)
+			_, err := rb.WriteMany([]int{1, 2})
+			require.NoError(t, err)
+
+			// Keep waking the writer without making room for it
+			stop := make(chan struct{})
+			defer close(stop)
+			go func() {
+				for {
+					select {
+					case <-stop:
+						return
+					case <-time.After(10 * time.Millisecond):
+						rb.WakeUpOneWriter()
+					}
+				}
+			}()
+
+			start := time.Now()
+			err = write(rb)
+			elapsed := time.Since(start)
+
+			assert.ErrorIs(t, err, errors.ErrWriteTimeout)
+			assert.GreaterOrEqual(t, elapsed, timeout)
+			assert.Less(t, elapsed, 2*timeout)
+		})
+	}
+}
+
+func TestSafeResetWakesBlockedWriters(t *testing.T) {
+	rb := ringbuffer.New[int](2).WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, rb.Write(i+3))
+		}()
+	}
+	require.Eventually(t, func() bool { return rb.GetBlockedWriters() == 2 }, time.Second, time.Millisecond)
+
+	rb.SafeReset()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("SafeReset should let blocked writers use the freed space")
+	}
+
+	items, err := rb.GetN(2)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int{3, 4}, items)
+}
+
+func TestSafeResetDuringBlockedOperations(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true).WithTimeout(10 * time.Millisecond)
+	require.NotNil(t, rb)
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := range 200 {
+				rb.Write(i*1000 + j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				rb.GetOne()
+			}
+		}()
+	}
+
+	for range 100 {
+		rb.SafeReset()
+		assert.LessOrEqual(t, rb.Length(false), rb.Capacity())
+	}
+	wg.Wait()
+
+	// Still consistent once everyone is done
+	rb.SafeReset()
+	assert.Equal(t, 0, rb.Length(false))
+	require.NoError(t, rb.Write(1))
+	item, err := rb.GetOne()
+	require.NoError(t, err)
+	assert.Equal(t, 1, item)
+}
+
+func TestClearBufferConcurrentWithReadsAndWrites(t *testing.T) {
+	rb := ringbuffer.New[int](8)
+	require.NotNil(t, rb)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := range 2 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; ; j++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				rb.Write(i*1_000_000 + j)
+				rb.GetBlockedWriters()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				rb.GetOne()
+			}
+		}()
+	}
+
+	for range 1000 {
+		rb.ClearBuffer()
+		assert.LessOrEqual(t, rb.Length(false), rb.Capacity())
+	}
+	close(stop)
+	wg.Wait()
+
+	rb.ClearBuffer()
+	assert.True(t, rb.IsEmpty())
+	assert.True(t, rb.IsEmptyFast())
+}
+
+func TestConcurrentWriteManyBatchesStayContiguous(t *testing.T) {
+	const producers, batches, batchLen = 4, 50, 5
+
+	cases := map[string]struct {
+		rb    func() *ringbuffer.RingBuffer[int]
+		write func(rb *ringbuffer.RingBuffer[int], batch []int) error
+	}{
+		"WriteMany": {
+			rb: func() *ringbuffer.RingBuffer[int] { return ringbuffer.New[int](8) },
+			write: func(rb *ringbuffer.RingBuffer[int], batch []int) error {
+				_, err := rb.WriteMany(batch)
+				return err
+			},
+		},
+		"Chunked WriteMany": {
+			rb: func() *ringbuffer.RingBuffer[int] {
+				return ringbuffer.New[int](8).WithMaxBatch(2).WithContiguousBatches(true)
+			},
+			write: func(rb *ringbuffer.RingBuffer[int], batch []int) error {
+				_, err := rb.WriteMany(batch)
+				return err
+			},
+		},
+		"WriteManyProgress": {
+			rb: func() *ringbuffer.RingBuffer[int] { return ringbuffer.New[int](3).WithContiguousBatches(true) },
+			write: func(rb *ringbuffer.RingBuffer[int], batch []int) error {
+				_, err := rb.WriteManyProgress(batch, nil)
+				return err
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rb := tc.rb().WithBlocking(true).WithTimeout(5 * time.Second)
+			require.NotNil(t, rb)
+
+			var wg sync.WaitGroup
+			for p := range producers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for b := range batches {
+						// Every item encodes its producer, batch and position
+						batch := make([]int, batchLen)
+						for i := range batch {
+							batch[i] = (p*batches+b)*batchLen + i
+						}
+						assert.NoError(t, tc.write(rb, batch))
+					}
+				}()
+			}
+
+			got := make([]int, 0, producers*batches*batchLen)
+			for len(got) < cap(got) {
+				item, err := rb.GetOne()
+				require.NoError(t, err)
+				got = append(got, item)
+			}
+			wg.Wait()
+
+			// Read in runs of batchLen, each run must be one whole batch in order
+			for i := 0; i < len(got); i += batchLen {
+				first := got[i]
+				require.Zero(t, first%batchLen, "batch starting at %d was split", i)
+				for j := range batchLen {
+					require.Equal(t, first+j, got[i+j], "batch starting at %d was interleaved", i)
+				}
+			}
+		})
+	}
+}
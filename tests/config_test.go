@@ -0,0 +1,102 @@
+package test
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/config"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingBufferApplyConfig(t *testing.T) {
+	rb := ringbuffer.New[int](2)
+	require.NotNil(t, rb)
+
+	rb.ApplyConfig(nil)
+	rb.ApplyConfig(&config.RingBufferConfig[int]{Overwrite: true})
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+	items, err := rb.GetN(2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, items)
+
+	// A timeout alone enables blocking
+	rb.ApplyConfig(&config.RingBufferConfig[int]{RTimeout: 20 * time.Millisecond})
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, errors.ErrReadTimeout)
+
+	// Disabling blocking releases the waiting reader
+	done := make(chan error, 1)
+	rb.ApplyConfig(&config.RingBufferConfig[int]{Block: true})
+	go func() {
+		_, err := rb.GetOne()
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	rb.ApplyConfig(&config.RingBufferConfig[int]{})
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, errors.ErrIsEmpty)
+	case <-time.After(time.Second):
+		t.Fatal("reader should stop waiting once blocking is disabled")
+	}
+}
+
+func TestRingBufferApplyConfigConcurrent(t *testing.T) {
+	rb := ringbuffer.New[int](8).WithBlocking(true).WithTimeout(10 * time.Millisecond)
+	require.NotNil(t, rb)
+
+	configs := []*config.RingBufferConfig[int]{
+		{Block: true, RTimeout: 5 * time.Millisecond, WTimeout: 5 * time.Millisecond},
+		{Overwrite: true, RTimeout: time.Millisecond},
+		{},
+		{Block: true, WTimeout: 2 * time.Millisecond, Overwrite: true},
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				rb.Write(i)
+				rb.WriteMany([]int{i, i + 1})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				rb.GetOne()
+				rb.GetN(2)
+			}
+		}()
+	}
+
+	for i, start := 0, time.Now(); time.Since(start) < 200*time.Millisecond; i++ {
+		rb.ApplyConfig(configs[i%len(configs)])
+		runtime.Gosched()
+	}
+
+	// Settle in a mode where nobody can stay blocked
+	rb.ApplyConfig(&config.RingBufferConfig[int]{})
+	close(stop)
+	wg.Wait()
+	assert.NoError(t, rb.CheckInvariants())
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumeBatch(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	err := rb.ConsumeBatch(3, func(items []int) error { return nil })
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+
+	err = rb.ConsumeBatch(0, func(items []int) error { return nil })
+	assert.ErrorIs(t, err, errors.ErrInvalidLength)
+
+	_, err = rb.WriteMany([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+
+	// A failing callback leaves the items queued
+	failure := fmt.Errorf("processing failed")
+	var seen []int
+	err = rb.ConsumeBatch(3, func(items []int) error {
+		seen = append(seen, items...)
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, []int{1, 2, 3}, seen)
+	assert.Equal(t, 4, rb.Length(false))
+
+	// A successful callback commits the batch
+	seen = nil
+	err = rb.ConsumeBatch(3, func(items []int) error {
+		seen = append(seen, items...)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, seen)
+	assert.Equal(t, 1, rb.Length(false))
+
+	// Requests larger than the queue hand out what is there
+	seen = nil
+	err = rb.ConsumeBatch(10, func(items []int) error {
+		seen = append(seen, items...)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4}, seen)
+	assert.True(t, rb.IsEmpty())
+}
+
+func TestConsumeBatchReentrant(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2})
+	require.NoError(t, err)
+
+	err = rb.ConsumeBatch(1, func(items []int) error {
+		// The lock is not held, so the buffer stays usable
+		require.NoError(t, rb.Write(3))
+		return rb.ConsumeBatch(1, func([]int) error { return nil })
+	})
+	assert.ErrorIs(t, err, errors.ErrConsumeInProgress)
+	assert.Equal(t, 3, rb.Length(false))
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingBufferGetOneCtxCancel(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	// Many readers parked on the same cond, only one of them watching ctx
+	var wg sync.WaitGroup
+	var returned atomic.Int32
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := rb.GetOne()
+			assert.ErrorIs(t, err, io.EOF)
+			returned.Add(1)
+		}()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := rb.GetOneCtx(ctx)
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("GetOneCtx didn't return after ctx was cancelled")
+	}
+
+	// The other readers went back to waiting
+	assert.Zero(t, returned.Load())
+
+	require.NoError(t, rb.Close())
+	wg.Wait()
+}
+
+func TestRingBufferGetOneCtxReadsQueuedItem(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true)
+	require.NotNil(t, rb)
+	require.NoError(t, rb.Write(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	item, err := rb.GetOneCtx(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, item)
+
+	_, err = rb.GetOneCtx(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRingBufferWriteCtxDeadline(t *testing.T) {
+	rb := ringbuffer.New[int](1).WithBlocking(true)
+	require.NotNil(t, rb)
+	require.NoError(t, rb.WriteCtx(context.Background(), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := rb.WriteCtx(ctx, 2)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Nothing was written, and the buffer is still usable
+	item, err := rb.GetOne()
+	require.NoError(t, err)
+	assert.Equal(t, 1, item)
+	require.NoError(t, rb.WriteCtx(ctx, 3))
+}
+
+func TestRingBufferWriteCtxWokenByRead(t *testing.T) {
+	rb := ringbuffer.New[int](1).WithBlocking(true)
+	require.NotNil(t, rb)
+	require.NoError(t, rb.Write(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- rb.WriteCtx(ctx, 2) }()
+
+	time.Sleep(10 * time.Millisecond)
+	_, err := rb.GetOne()
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+
+	item, err := rb.GetOneCtx(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, item)
+}
@@ -52,6 +52,8 @@ func TestErrorCases(t *testing.T) {
 		// Test write timeout
 		err = rb.Write(2)
 		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, errors.ErrWriteTimeout)
+		assert.NotErrorIs(t, err, errors.ErrReadTimeout)
 
 		// Empty the buffer
 		_, err = rb.GetOne()
@@ -60,6 +62,11 @@ func TestErrorCases(t *testing.T) {
 		// Test read timeout
 		_, err = rb.GetOne()
 		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, errors.ErrReadTimeout)
+		assert.NotErrorIs(t, err, errors.ErrWriteTimeout)
+
+		// Timeouts don't stick: the buffer keeps working
+		require.NoError(t, rb.Write(3))
 	})
 
 	t.Run("View Operation Errors", func(t *testing.T) {
@@ -191,6 +198,14 @@ func TestErrorCases(t *testing.T) {
 		// Test writing more items than buffer size
 		items := make([]int, 6)
 		n, err = rb.WriteMany(items)
+		assert.ErrorIs(t, err, errors.ErrTooMuchDataToWrite)
+		assert.Equal(t, 0, n)
+
+		// Test writing more items than free space
+		n, err = rb.WriteMany(items[:3])
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+		n, err = rb.WriteMany(items[:3])
 		assert.ErrorIs(t, err, errors.ErrIsFull)
 		assert.Equal(t, 0, n)
 	})
@@ -201,7 +216,7 @@ func TestErrorCases(t *testing.T) {
 
 		// Test getting more items than buffer size
 		_, err := rb.GetN(6)
-		assert.ErrorIs(t, err, errors.ErrIsEmpty)
+		assert.ErrorIs(t, err, errors.ErrInvalidLength)
 
 		// Fill buffer
 		for i := range 5 {
@@ -217,5 +232,11 @@ func TestErrorCases(t *testing.T) {
 		// Test getting more items than available
 		_, err = rb.GetN(1)
 		assert.ErrorIs(t, err, errors.ErrIsEmpty)
+
+		// More than capacity is invalid even on a full buffer
+		_, err = rb.WriteMany([]int{1, 2, 3, 4, 5})
+		require.NoError(t, err)
+		_, err = rb.GetN(6)
+		assert.ErrorIs(t, err, errors.ErrInvalidLength)
 	})
 }
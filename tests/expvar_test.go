@@ -0,0 +1,66 @@
+package test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+	require.NoError(t, rb.PublishExpvar("TestPublishExpvar"))
+
+	stats := func() map[string]int64 {
+		v := expvar.Get("TestPublishExpvar")
+		require.NotNil(t, v)
+
+		var m map[string]int64
+		require.NoError(t, json.Unmarshal([]byte(v.String()), &m))
+		return m
+	}
+
+	assert.Equal(t, map[string]int64{
+		"length": 0, "capacity": 4, "writes": 0, "reads": 0, "drops": 0,
+		"blocked_readers": 0, "blocked_writers": 0,
+	}, stats())
+
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+
+	m := stats()
+	assert.Equal(t, int64(1), m["length"])
+	assert.Equal(t, int64(3), m["writes"])
+	assert.Equal(t, int64(2), m["reads"])
+
+	// Blocked readers show up while they wait
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		rb.GetOne()
+	}()
+	require.Eventually(t, func() bool { return stats()["blocked_readers"] == 1 }, time.Second, time.Millisecond)
+	require.NoError(t, rb.Write(4))
+	<-done
+	assert.Equal(t, int64(0), stats()["blocked_readers"])
+
+	// Overwritten items are counted as drops
+	rb.WithOverwrite(true)
+	_, err = rb.WriteMany([]int{5, 6, 7, 8, 9, 10})
+	require.NoError(t, err)
+	m = stats()
+	assert.Equal(t, int64(2), m["drops"])
+	assert.Equal(t, int64(4), m["length"])
+
+	assert.ErrorIs(t, rb.PublishExpvar("TestPublishExpvar"), errors.ErrAlreadyPublished)
+}
@@ -0,0 +1,188 @@
+package test
+
+import (
+	"container/list"
+	"slices"
+	"testing"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// fifoModel is the reference a fuzzed buffer is compared against.
+type fifoModel struct {
+	items     *list.List
+	size      int
+	overwrite bool
+}
+
+func (m *fifoModel) push(v int) {
+	m.items.PushBack(v)
+	if m.items.Len() > m.size {
+		m.items.Remove(m.items.Front())
+	}
+}
+
+func (m *fifoModel) front(n int) []int {
+	out := make([]int, 0, n)
+	for e := m.items.Front(); e != nil && len(out) < n; e = e.Next() {
+		out = append(out, e.Value.(int))
+	}
+	return out
+}
+
+func (m *fifoModel) pop(n int) []int {
+	out := m.front(n)
+	for range out {
+		m.items.Remove(m.items.Front())
+	}
+	return out
+}
+
+func (m *fifoModel) back(n int) []int {
+	out := make([]int, 0, n)
+	for e := m.items.Back(); e != nil && len(out) < n; e = e.Prev() {
+		out = append(out, e.Value.(int))
+	}
+	return out
+}
+
+// FuzzRingBuffer applies the operations encoded in ops to a non-blocking buffer and
+// checks every result against a container/list model, along with CheckInvariants.
+// The first byte picks the size, the second overwrite mode, then each operation
+// takes two bytes: which one and its argument.
+func FuzzRingBuffer(f *testing.F) {
+	f.Add([]byte{4, 0, 0, 1, 1, 3, 3, 2, 2, 0, 4, 1})
+	f.Add([]byte{3, 1, 1, 5, 2, 0, 0, 9, 1, 2, 6, 3, 7, 3, 4, 2})
+	f.Add([]byte{1, 0, 0, 0, 0, 0, 2, 0, 8, 1, 1, 1})
+	f.Add([]byte{7, 0, 1, 6, 3, 4, 1, 6, 5, 7, 6, 7, 7, 7, 8, 7, 4, 3})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		if len(ops) < 2 {
+			return
+		}
+
+		size := 1 + int(ops[0])%8
+		overwrite := ops[1]%2 == 1
+		// The sizer lets CheckInvariants verify the byte total too
+		rb := ringbuffer.New[int](size).WithOverwrite(overwrite).WithSizer(func(v int) int { return v%5 + 1 })
+		require.NotNil(t, rb)
+		model := &fifoModel{items: list.New(), size: size, overwrite: overwrite}
+
+		next := 0
+		batch := func(n int) []int {
+			items := make([]int, n)
+			for i := range items {
+				items[i] = next
+				next++
+			}
+			return items
+		}
+
+		for i := 2; i+1 < len(ops); i += 2 {
+			op, n := ops[i]%9, 1+int(ops[i+1])%(size+1)
+			length := model.items.Len()
+
+			switch op {
+			case 0:
+				err := rb.Write(next)
+				if !overwrite && length == size {
+					require.ErrorIs(t, err, errors.ErrIsFull, "op %d", i)
+				} else {
+					require.NoError(t, err, "op %d", i)
+					model.push(next)
+				}
+				next++
+			case 1:
+				items := batch(n)
+				written, err := rb.WriteMany(items)
+				switch {
+				case !overwrite && n > size:
+					require.ErrorIs(t, err, errors.ErrTooMuchDataToWrite, "op %d", i)
+				case !overwrite && n > size-length:
+					require.ErrorIs(t, err, errors.ErrIsFull, "op %d", i)
+				default:
+					require.NoError(t, err, "op %d", i)
+					require.Equal(t, n, written, "op %d", i)
+					for _, v := range items {
+						model.push(v)
+					}
+				}
+			case 2:
+				item, err := rb.GetOne()
+				if length == 0 {
+					require.ErrorIs(t, err, errors.ErrIsEmpty, "op %d", i)
+				} else {
+					require.NoError(t, err, "op %d", i)
+					require.Equal(t, model.pop(1)[0], item, "op %d", i)
+				}
+			case 3:
+				items, err := rb.GetN(n)
+				switch {
+				case n > size:
+					require.ErrorIs(t, err, errors.ErrInvalidLength, "op %d", i)
+				case n > length:
+					require.ErrorIs(t, err, errors.ErrIsEmpty, "op %d", i)
+				default:
+					require.NoError(t, err, "op %d", i)
+					require.Equal(t, model.pop(n), items, "op %d", i)
+				}
+			case 4:
+				// Skips n items through a view
+				part1, part2, err := rb.GetNView(n)
+				switch {
+				case n > size:
+					require.ErrorIs(t, err, errors.ErrInvalidLength, "op %d", i)
+				case n > length:
+					require.ErrorIs(t, err, errors.ErrIsEmpty, "op %d", i)
+				default:
+					require.NoError(t, err, "op %d", i)
+					require.Equal(t, model.pop(n), slices.Concat(part1, part2), "op %d", i)
+				}
+			case 5:
+				item, err := rb.PeekOne()
+				if length == 0 {
+					require.ErrorIs(t, err, errors.ErrIsEmpty, "op %d", i)
+				} else {
+					require.NoError(t, err, "op %d", i)
+					require.Equal(t, model.front(1)[0], item, "op %d", i)
+				}
+			case 6:
+				items, err := rb.PeekN(n)
+				switch {
+				case length == 0:
+					require.ErrorIs(t, err, errors.ErrIsEmpty, "op %d", i)
+				case n > length:
+					require.ErrorIs(t, err, errors.ErrTooMuchDataToPeek, "op %d", i)
+				default:
+					require.NoError(t, err, "op %d", i)
+					require.Equal(t, model.front(n), items, "op %d", i)
+				}
+			case 7:
+				items, err := rb.PeekRecentN(n)
+				switch {
+				case length == 0:
+					require.ErrorIs(t, err, errors.ErrIsEmpty, "op %d", i)
+				case n > length:
+					require.ErrorIs(t, err, errors.ErrTooMuchDataToPeek, "op %d", i)
+				default:
+					require.NoError(t, err, "op %d", i)
+					require.Equal(t, model.back(n), items, "op %d", i)
+				}
+			case 8:
+				data := make([]int, n)
+				read, err := rb.Read(data)
+				if length == 0 {
+					require.ErrorIs(t, err, errors.ErrIsEmpty, "op %d", i)
+				} else {
+					require.NoError(t, err, "op %d", i)
+					require.Equal(t, model.pop(n), data[:read], "op %d", i)
+				}
+			}
+
+			require.NoError(t, rb.CheckInvariants(), "op %d", i)
+			require.Equal(t, model.items.Len(), rb.Length(false), "op %d", i)
+		}
+	})
+}
@@ -2,10 +2,16 @@ package test
 
 import (
 	"context"
+	"io"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -86,7 +92,383 @@ func TestRingBufferGetManyTimeout(t *testing.T) {
 	rb := ringbuffer.New[*TestValue](2).WithTimeout(100 * time.Millisecond)
 	require.NotNil(t, rb)
 
-	items, err := rb.GetN(5)
+	items, err := rb.GetN(2)
 	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, errors.ErrReadTimeout)
 	assert.Equal(t, 0, len(items))
+
+	// more than capacity fails fast instead of waiting for the timeout
+	start := time.Now()
+	_, err = rb.GetN(5)
+	assert.ErrorIs(t, err, errors.ErrInvalidLength)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestRingBufferGetManyWokenByWriteMany(t *testing.T) {
+	rb := ringbuffer.New[int](10).WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+
+	done := make(chan []int, 1)
+	go func() {
+		items, err := rb.GetN(5)
+		assert.NoError(t, err)
+		done <- items
+	}()
+	require.Eventually(t, func() bool {
+		return rb.GetBlockedReaders() == 1
+	}, time.Second, time.Millisecond)
+
+	n, err := rb.WriteMany([]int{1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	select {
+	case items := <-done:
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, items)
+	case <-time.After(time.Second):
+		t.Fatal("GetN(5) should complete after a WriteMany of 5 items")
+	}
+}
+
+func TestRingBufferRead(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+
+	data := make([]int, 3)
+	n, err := rb.Read(data)
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+	assert.Equal(t, 0, n)
+
+	n, err = rb.Read(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	// Items wrap around the buffer end
+	_, err = rb.WriteMany([]int{0, 0, 1, 2})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+	_, err = rb.WriteMany([]int{3, 4})
+	require.NoError(t, err)
+
+	n, err = rb.Read(data)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []int{1, 2, 3}, data)
+
+	// Partial read
+	n, err = rb.Read(data)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 4, data[0])
+
+	require.NoError(t, rb.Close())
+	n, err = rb.Read(data)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 0, n)
+}
+
+func TestRingBufferReadBlocking(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+
+	done := make(chan []int, 1)
+	go func() {
+		data := make([]int, 4)
+		n, err := rb.Read(data)
+		assert.NoError(t, err)
+		done <- data[:n]
+	}()
+	require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 1 }, time.Second, time.Millisecond)
+
+	require.NoError(t, rb.Write(7))
+	select {
+	case items := <-done:
+		assert.Equal(t, []int{7}, items)
+	case <-time.After(time.Second):
+		t.Fatal("Read should return once one item is available")
+	}
+
+	rb.WithTimeout(time.Millisecond)
+	_, err := rb.Read(make([]int, 1))
+	assert.ErrorIs(t, err, errors.ErrReadTimeout)
+}
+
+func TestRingBufferDrainInto(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	dst, n := rb.DrainInto(nil)
+	assert.Nil(t, dst)
+	assert.Equal(t, 0, n)
+
+	// Wrap the queued items around the buffer end
+	_, err := rb.WriteMany([]int{0, 0, 0, 1})
+	require.NoError(t, err)
+	_, err = rb.GetN(3)
+	require.NoError(t, err)
+	_, err = rb.WriteMany([]int{2, 3, 4})
+	require.NoError(t, err)
+
+	dst = make([]int, 1, 8)
+	dst[0] = -1
+	backing := &dst[:cap(dst)][0]
+	dst, n = rb.DrainInto(dst)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, []int{-1, 1, 2, 3, 4}, dst)
+	assert.Same(t, backing, &dst[0], "spare capacity must be reused")
+	assert.True(t, rb.IsEmpty())
+
+	// Reusing the slice across flushes doesn't allocate
+	batch := []int{5, 6, 7}
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = rb.WriteMany(batch)
+		dst, _ = rb.DrainInto(dst[:0])
+	})
+	assert.Zero(t, allocs)
+	assert.Equal(t, []int{5, 6, 7}, dst)
+
+	require.NoError(t, rb.Close())
+	dst, n = rb.DrainInto(dst[:0])
+	assert.Empty(t, dst)
+	assert.Equal(t, 0, n)
+}
+
+func TestRingBufferExchange(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+	assert.Nil(t, rb.Exchange())
+
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+	_, err = rb.WriteMany([]int{4, 5})
+	require.NoError(t, err)
+
+	items := rb.Exchange()
+	assert.Equal(t, []int{2, 3, 4, 5}, items)
+	assert.True(t, rb.IsEmpty())
+
+	// The buffer is reusable right away, independently of the returned slice
+	_, err = rb.WriteMany([]int{6, 7, 8, 9})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3, 4, 5}, items)
+	assert.Equal(t, []int{6, 7, 8, 9}, rb.Exchange())
+}
+
+func TestRingBufferExchangeConcurrentWriters(t *testing.T) {
+	const writers, perWriter = 4, 500
+	rb := ringbuffer.New[int](16).WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWriter {
+				assert.NoError(t, rb.Write(w*perWriter+i))
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	seen := make(map[int]bool)
+	collect := func(items []int) {
+		for _, item := range items {
+			require.False(t, seen[item], "item %d returned twice", item)
+			seen[item] = true
+		}
+	}
+	for {
+		select {
+		case <-done:
+			collect(rb.Exchange())
+			assert.Len(t, seen, writers*perWriter)
+			assert.True(t, rb.IsEmpty())
+			return
+		default:
+			collect(rb.Exchange())
+		}
+	}
+}
+
+// rotatedFull returns a full buffer of the given size whose read position is rot,
+// holding 0..size-1 in FIFO order.
+func rotatedFull(t *testing.T, size, rot int) *ringbuffer.RingBuffer[int] {
+	t.Helper()
+
+	rb := ringbuffer.New[int](size)
+	require.NotNil(t, rb)
+
+	for i := range rot {
+		require.NoError(t, rb.Write(-i))
+		_, err := rb.GetOne()
+		require.NoError(t, err)
+	}
+	for i := range size {
+		require.NoError(t, rb.Write(i))
+	}
+	require.True(t, rb.IsFull())
+
+	return rb
+}
+
+func TestRingBufferGetNEveryRotation(t *testing.T) {
+	const size = 5
+	want := []int{0, 1, 2, 3, 4}
+
+	for rot := range size {
+		for n := 1; n <= size; n++ {
+			rb := rotatedFull(t, size, rot)
+			items, err := rb.GetN(n)
+			require.NoError(t, err, "rot %d n %d", rot, n)
+			assert.Equal(t, want[:n], items, "rot %d n %d", rot, n)
+
+			if n < size {
+				rest, err := rb.GetN(size - n)
+				require.NoError(t, err, "rot %d n %d", rot, n)
+				assert.Equal(t, want[n:], rest, "rot %d n %d", rot, n)
+			}
+			assert.True(t, rb.IsEmpty())
+
+			rb = rotatedFull(t, size, rot)
+			peeked, err := rb.PeekN(n)
+			require.NoError(t, err, "rot %d n %d", rot, n)
+			assert.Equal(t, want[:n], peeked, "rot %d n %d", rot, n)
+
+			part1, part2, err := rb.GetNView(n)
+			require.NoError(t, err, "rot %d n %d", rot, n)
+			assert.Equal(t, want[:n], append(slices.Clone(part1), part2...), "rot %d n %d", rot, n)
+			assert.LessOrEqual(t, len(part1), size-rot, "rot %d n %d", rot, n)
+			assert.NoError(t, rb.CheckInvariants())
+		}
+	}
+}
+
+func TestRingBufferGetRange(t *testing.T) {
+	rb := ringbuffer.New[int](8).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	for _, bounds := range [][2]int{{0, 1}, {3, 2}, {1, 9}} {
+		_, err := rb.GetRange(bounds[0], bounds[1], time.Second)
+		assert.ErrorIs(t, err, errors.ErrInvalidLength, "bounds %v", bounds)
+	}
+
+	// Waits for min items
+	done := make(chan []int, 1)
+	go func() {
+		items, err := rb.GetRange(3, 5, 5*time.Second)
+		assert.NoError(t, err)
+		done <- items
+	}()
+
+	require.NoError(t, rb.Write(1))
+	require.NoError(t, rb.Write(2))
+	select {
+	case <-done:
+		t.Fatal("GetRange should wait for min items")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	require.NoError(t, rb.Write(3))
+	select {
+	case items := <-done:
+		assert.Equal(t, []int{1, 2, 3}, items)
+	case <-time.After(time.Second):
+		t.Fatal("GetRange should return once min items are queued")
+	}
+
+	// Caps at max items
+	_, err := rb.WriteMany([]int{4, 5, 6, 7, 8, 9, 10})
+	require.NoError(t, err)
+	items, err := rb.GetRange(2, 5, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 5, 6, 7, 8}, items)
+	assert.Equal(t, 2, rb.Length(false))
+}
+
+func TestRingBufferGetRangeTimeout(t *testing.T) {
+	rb := ringbuffer.New[int](8).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	_, err := rb.GetRange(1, 2, 10*time.Millisecond)
+	assert.ErrorIs(t, err, errors.ErrReadTimeout)
+
+	// Partial batch along with the timeout error
+	require.NoError(t, rb.Write(1))
+	items, err := rb.GetRange(2, 4, 10*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []int{1}, items)
+
+	rb.WithQuietRangeTimeout(true)
+	require.NoError(t, rb.Write(2))
+	items, err = rb.GetRange(2, 4, 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2}, items)
+
+	// Closed: the rest, then io.EOF
+	require.NoError(t, rb.Write(3))
+	require.NoError(t, rb.CloseGraceful())
+	items, err = rb.GetRange(2, 4, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3}, items)
+	_, err = rb.GetRange(2, 4, time.Second)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestRingBufferGetRangeNonBlocking(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+
+	require.NoError(t, rb.Write(1))
+	_, err := rb.GetRange(2, 3, 0)
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+
+	require.NoError(t, rb.Write(2))
+	items, err := rb.GetRange(2, 3, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, items)
+}
+
+func TestRingBufferGetNZeroesVacatedSlots(t *testing.T) {
+	type payload struct{ _ [64]byte }
+
+	const size = 4
+	rb := ringbuffer.New[*payload](size)
+	require.NotNil(t, rb)
+
+	// Move the read position so the next full read wraps
+	for range 2 {
+		require.NoError(t, rb.Write(&payload{}))
+		_, err := rb.GetOne()
+		require.NoError(t, err)
+	}
+
+	var collected atomic.Int32
+	for range size {
+		p := &payload{}
+		runtime.SetFinalizer(p, func(*payload) { collected.Add(1) })
+		require.NoError(t, rb.Write(p))
+	}
+
+	items, err := rb.GetN(size)
+	require.NoError(t, err)
+	require.Len(t, items, size)
+	items = nil
+
+	deadline := time.Now().Add(5 * time.Second)
+	for collected.Load() < size && time.Now().Before(deadline) {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, int32(size), collected.Load(), "the buffer still references consumed items")
+
+	runtime.KeepAlive(rb)
 }
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrowPreservesOrder(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+
+	assert.ErrorIs(t, rb.Grow(0), errors.ErrInvalidLength)
+
+	// Full buffer whose items wrap around the buffer end
+	_, err := rb.WriteMany([]int{0, 0, 1, 2})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+	_, err = rb.WriteMany([]int{3, 4})
+	require.NoError(t, err)
+	require.True(t, rb.IsFull())
+
+	gen := rb.Generation()
+	require.NoError(t, rb.Grow(3))
+	assert.False(t, rb.ValidateGeneration(gen), "Grow should invalidate views taken before")
+
+	assert.Equal(t, 7, rb.Capacity())
+	assert.Equal(t, 4, rb.Length(false))
+	assert.Equal(t, 3, rb.Free())
+	assert.False(t, rb.IsFull())
+
+	_, err = rb.WriteMany([]int{5, 6, 7})
+	require.NoError(t, err)
+	assert.True(t, rb.IsFull())
+
+	items, err := rb.GetN(7)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, items)
+}
+
+func TestGrowWakesWriters(t *testing.T) {
+	rb := ringbuffer.New[int](2).WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2})
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() { done <- rb.Write(3) }()
+	require.Eventually(t, func() bool { return rb.GetBlockedWriters() == 1 }, time.Second, time.Millisecond)
+
+	require.NoError(t, rb.Grow(1))
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Grow should wake blocked writers")
+	}
+
+	items, err := rb.GetN(3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, items)
+}
+
+func TestRingBufferResize(t *testing.T) {
+	for rot := range 4 {
+		rb := rotatedFull(t, 4, rot)
+
+		// Shrinking below the queued items fails without changing anything
+		assert.ErrorIs(t, rb.Resize(3), errors.ErrInvalidLength)
+		assert.Equal(t, 4, rb.Capacity())
+
+		require.NoError(t, rb.Resize(6), "rot %d", rot)
+		assert.Equal(t, 6, rb.Capacity())
+		_, err := rb.WriteMany([]int{4, 5})
+		require.NoError(t, err)
+
+		items, err := rb.GetN(3)
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 2}, items, "rot %d", rot)
+
+		require.NoError(t, rb.Resize(3), "rot %d", rot)
+		assert.Equal(t, 3, rb.Capacity())
+		assert.True(t, rb.IsFull())
+		require.NoError(t, rb.CheckInvariants())
+
+		items, err = rb.GetN(3)
+		require.NoError(t, err)
+		assert.Equal(t, []int{3, 4, 5}, items, "rot %d", rot)
+	}
+
+	rb := ringbuffer.New[int](4)
+	assert.ErrorIs(t, rb.Resize(0), errors.ErrInvalidLength)
+}
+
+func TestRingBufferResizeWakesBlockedWriter(t *testing.T) {
+	rb := ringbuffer.New[int](2).WithBlocking(true)
+	require.NotNil(t, rb)
+	_, err := rb.WriteMany([]int{1, 2})
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() { done <- rb.Write(3) }()
+	require.Eventually(t, func() bool { return rb.GetBlockedWriters() == 1 }, time.Second, time.Millisecond)
+
+	require.NoError(t, rb.Resize(3))
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("the blocked writer should proceed once the buffer is resized")
+	}
+
+	items, err := rb.GetN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, items)
+}
@@ -1,7 +1,9 @@
 package test
 
 import (
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/AlexsanderHamir/ringbuffer"
 	"github.com/AlexsanderHamir/ringbuffer/errors"
@@ -63,3 +65,138 @@ func TestRingBufferPreWriteBlockHook(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, hookCalled)
 }
+
+func TestRingBufferAsyncHooks(t *testing.T) {
+	t.Run("Slow hooks run off the lock, in order", func(t *testing.T) {
+		release := make(chan struct{})
+		var discarded []int
+		var batches [][]int
+
+		rb := ringbuffer.New[int](2).WithOverwrite(true).WithAsyncHooks(16)
+		require.NotNil(t, rb)
+		rb.WithOnDiscard(func(item int) {
+			<-release
+			discarded = append(discarded, item)
+		})
+		rb.WithOnDiscardMany(func(items []int) { batches = append(batches, items) })
+
+		for i := range 6 {
+			require.NoError(t, rb.Write(i))
+		}
+
+		// The buffer keeps working while the hook is stuck
+		assert.Equal(t, 2, rb.Length(false))
+		items, err := rb.GetN(2)
+		require.NoError(t, err)
+		assert.Equal(t, []int{4, 5}, items)
+		_, err = rb.WriteMany([]int{6, 7})
+		require.NoError(t, err)
+
+		close(release)
+		require.NoError(t, rb.Close())
+		rb.Wait()
+
+		assert.Equal(t, []int{0, 1, 2, 3}, discarded)
+		assert.Equal(t, [][]int{{0}, {1}, {2}, {3}}, batches, "batches are copies, not views")
+	})
+
+	t.Run("Full queue makes writes wait off the lock", func(t *testing.T) {
+		release := make(chan struct{})
+		var discarded []int
+
+		rb := ringbuffer.New[int](1).WithOverwrite(true).WithAsyncHooks(1)
+		require.NotNil(t, rb)
+		rb.WithOnDiscard(func(item int) {
+			<-release
+			// Hooks may call back into the buffer
+			_ = rb.Length(false)
+			discarded = append(discarded, item)
+		})
+
+		// The dispatcher holds one call and the queue another, the next write waits
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := range 10 {
+				assert.NoError(t, rb.Write(i))
+			}
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("writes queued hook calls past the queue without waiting")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		// The waiting write released the lock
+		assert.Equal(t, 1, rb.Length(false))
+
+		close(release)
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("writes should resume once the hooks catch up")
+		}
+
+		require.NoError(t, rb.Close())
+		rb.Wait()
+
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8}, discarded)
+	})
+
+	t.Run("Drop on full", func(t *testing.T) {
+		release := make(chan struct{})
+		var discarded []int
+
+		rb := ringbuffer.New[int](1).WithOverwrite(true).WithAsyncHooks(1).WithAsyncHooksDropOnFull(true)
+		require.NotNil(t, rb)
+		rb.WithOnDiscard(func(item int) {
+			<-release
+			discarded = append(discarded, item)
+		})
+
+		// The dispatcher holds one call and the queue another, the rest are dropped
+		// instead of making the writes wait
+		for i := range 10 {
+			require.NoError(t, rb.Write(i))
+		}
+
+		close(release)
+		require.NoError(t, rb.Close())
+		rb.Wait()
+
+		require.NotEmpty(t, discarded)
+		assert.Less(t, len(discarded), 9)
+		assert.Equal(t, 0, discarded[0])
+		assert.IsIncreasing(t, discarded)
+	})
+}
+
+func TestRingBufferOnTimeout(t *testing.T) {
+	var mu sync.Mutex
+	var ops []string
+	rb := ringbuffer.New[int](2).WithBlocking(true).WithTimeout(5 * time.Millisecond).
+		WithOnTimeout(func(op string) {
+			mu.Lock()
+			ops = append(ops, op)
+			mu.Unlock()
+		})
+
+	_, err := rb.GetOne()
+	assert.ErrorIs(t, err, errors.ErrReadTimeout)
+	_, err = rb.GetN(2)
+	assert.ErrorIs(t, err, errors.ErrReadTimeout)
+	_, _, err = rb.GetNView(2)
+	assert.ErrorIs(t, err, errors.ErrReadTimeout)
+
+	_, err = rb.WriteMany([]int{1, 2})
+	require.NoError(t, err)
+	assert.ErrorIs(t, rb.Write(3), errors.ErrWriteTimeout)
+	_, err = rb.WriteMany([]int{3})
+	assert.ErrorIs(t, err, errors.ErrWriteTimeout)
+
+	rb.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	assert.ElementsMatch(t, []string{"GetOne", "GetN", "GetNView", "Write", "WriteMany"}, ops)
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+
+	assert.Equal(t, ringbuffer.BufferView[int]{Capacity: 4, Items: []int{}}, rb.Inspect(true))
+
+	_, err := rb.WriteMany([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+	_, err = rb.WriteMany([]int{5, 6})
+	require.NoError(t, err)
+
+	// Wrapped and full
+	view := rb.Inspect(true)
+	assert.Equal(t, ringbuffer.BufferView[int]{Length: 4, Capacity: 4, IsFull: true, Items: []int{3, 4, 5, 6}}, view)
+
+	// Items is a copy that outlives later operations
+	_, err = rb.GetN(4)
+	require.NoError(t, err)
+	_, err = rb.WriteMany([]int{7, 8, 9})
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5, 6}, view.Items)
+
+	// Metadata only
+	view = rb.Inspect(false)
+	assert.Equal(t, ringbuffer.BufferView[int]{Length: 3, Capacity: 4}, view)
+	assert.Nil(t, view.Items)
+
+	var nilBuffer *ringbuffer.RingBuffer[int]
+	assert.Equal(t, ringbuffer.BufferView[int]{}, nilBuffer.Inspect(true))
+}
+
+func TestRingBufferString(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+	assert.Equal(t, "RingBuffer(len=0 cap=4 full=false [])", rb.String())
+
+	// Wrapped around the buffer end
+	_, err := rb.WriteMany([]int{0, 0, 1})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+	_, err = rb.WriteMany([]int{2, 3, 4})
+	require.NoError(t, err)
+	assert.Equal(t, "RingBuffer(len=4 cap=4 full=true [1 2 3 4])", fmt.Sprintf("%v", rb))
+
+	big := ringbuffer.New[int](40)
+	for i := range 40 {
+		require.NoError(t, big.Write(i))
+	}
+	s := big.String()
+	assert.True(t, strings.HasPrefix(s, "RingBuffer(len=40 cap=40 full=true [0 1 2 "), s)
+	assert.True(t, strings.HasSuffix(s, " 30 31 ...])"), s)
+
+	var nilRB *ringbuffer.RingBuffer[int]
+	assert.Equal(t, "RingBuffer(nil)", nilRB.String())
+}
@@ -47,7 +47,7 @@ func TestRingBufferInvalidOperations(t *testing.T) {
 	assert.ErrorIs(t, err, errors.ErrInvalidLength, "GetMany with negative length should return ErrInvalidLength")
 
 	_, err = rb.GetN(11)
-	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+	assert.ErrorIs(t, err, errors.ErrInvalidLength, "GetMany with length over capacity should return ErrInvalidLength")
 
 	_, err = rb.PeekN(0)
 	assert.ErrorIs(t, err, errors.ErrInvalidLength, "PeekMany with length 0 should return ErrInvalidLength")
@@ -88,3 +88,82 @@ func TestRingBufferInvalidOperations(t *testing.T) {
 	_, err = rb.WriteMany(nil)
 	assert.NoError(t, err, "WriteMany with nil slice should not return error")
 }
+
+func TestRingBufferNonPositiveCounts(t *testing.T) {
+	setups := map[string]func() *ringbuffer.RingBuffer[int]{
+		"Empty": func() *ringbuffer.RingBuffer[int] { return ringbuffer.New[int](4) },
+		"Queued": func() *ringbuffer.RingBuffer[int] {
+			rb := ringbuffer.New[int](4)
+			_, err := rb.WriteMany([]int{1, 2})
+			require.NoError(t, err)
+			return rb
+		},
+		"MaxBatch": func() *ringbuffer.RingBuffer[int] { return ringbuffer.New[int](4).WithMaxBatch(1) },
+		"Closed": func() *ringbuffer.RingBuffer[int] {
+			rb := ringbuffer.New[int](4)
+			require.NoError(t, rb.Close())
+			return rb
+		},
+	}
+
+	// A count that went to zero or below is a bug, never a silent empty read
+	for name, setup := range setups {
+		t.Run(name, func(t *testing.T) {
+			for _, n := range []int{0, -1} {
+				rb := setup()
+				length := rb.Length(false)
+
+				items, err := rb.GetN(n)
+				assert.ErrorIs(t, err, errors.ErrInvalidLength)
+				assert.Nil(t, items)
+
+				_, err = rb.PeekN(n)
+				assert.ErrorIs(t, err, errors.ErrInvalidLength)
+
+				_, _, err = rb.GetNView(n)
+				assert.ErrorIs(t, err, errors.ErrInvalidLength)
+
+				_, _, err = rb.PeekNView(n)
+				assert.ErrorIs(t, err, errors.ErrInvalidLength)
+
+				_, _, err = rb.GetUpToNView(n)
+				assert.ErrorIs(t, err, errors.ErrInvalidLength)
+
+				err = rb.ConsumeBatch(n, func([]int) error { return nil })
+				assert.ErrorIs(t, err, errors.ErrInvalidLength)
+
+				assert.Equal(t, length, rb.Length(false), "nothing must be read")
+			}
+		})
+	}
+}
+
+func TestRingBufferZeroValue(t *testing.T) {
+	var rb ringbuffer.RingBuffer[int]
+
+	assert.ErrorIs(t, rb.Write(1), errors.ErrNotInitialized)
+	_, err := rb.WriteMany([]int{1, 2})
+	assert.ErrorIs(t, err, errors.ErrNotInitialized)
+	_, err = rb.WriteManyMulti([]int{1}, []int{2})
+	assert.ErrorIs(t, err, errors.ErrNotInitialized)
+
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, errors.ErrNotInitialized)
+	_, err = rb.GetN(1)
+	assert.ErrorIs(t, err, errors.ErrNotInitialized)
+	_, _, err = rb.GetNView(1)
+	assert.ErrorIs(t, err, errors.ErrNotInitialized)
+	_, err = rb.PeekOne()
+	assert.ErrorIs(t, err, errors.ErrNotInitialized)
+	_, err = rb.Read(make([]int, 1))
+	assert.ErrorIs(t, err, errors.ErrNotInitialized)
+	assert.ErrorIs(t, rb.Grow(1), errors.ErrNotInitialized)
+
+	// Blocking can't make a zero-value buffer wait forever
+	rb.WithBlocking(true)
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, errors.ErrNotInitialized)
+
+	assert.Equal(t, 0, rb.Length(false))
+	assert.NotPanics(t, func() { rb.Close() })
+}
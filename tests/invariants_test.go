@@ -0,0 +1,50 @@
+package test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckInvariants(t *testing.T) {
+	var nilBuffer *ringbuffer.RingBuffer[int]
+	assert.ErrorIs(t, nilBuffer.CheckInvariants(), errors.ErrNilBuffer)
+
+	buffers := map[string]*ringbuffer.RingBuffer[int]{
+		"Default":   ringbuffer.New[int](7),
+		"Overwrite": ringbuffer.New[int](7).WithOverwrite(true),
+		"Seqs":      ringbuffer.New[int](7).WithSeqTracking(true),
+		"Unbounded": ringbuffer.NewUnbounded[int](),
+	}
+
+	for name, rb := range buffers {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			require.NoError(t, rb.CheckInvariants())
+
+			for i := range 2000 {
+				switch rng.Intn(6) {
+				case 0:
+					rb.Write(i)
+				case 1:
+					rb.WriteMany(make([]int, rng.Intn(9)))
+				case 2:
+					rb.GetOne()
+				case 3:
+					rb.GetN(rng.Intn(5) + 1)
+				case 4:
+					rb.GetUpToNView(rng.Intn(5) + 1)
+				case 5:
+					if rng.Intn(20) == 0 {
+						rb.ClearBuffer()
+					}
+				}
+				require.NoError(t, rb.CheckInvariants(), "after operation %d", i)
+			}
+		})
+	}
+}
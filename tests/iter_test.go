@@ -0,0 +1,275 @@
+package test
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumeSeq(t *testing.T) {
+	for _, tracked := range []bool{false, true} {
+		rb := ringbuffer.New[string](3).WithOverwrite(true).WithSeqTracking(tracked)
+		require.NotNil(t, rb)
+
+		// a b c d e: a and b are overwritten, c d e wrap around the buffer end
+		for _, item := range []string{"a", "b", "c", "d", "e"} {
+			require.NoError(t, rb.Write(item))
+		}
+
+		var seqs []uint64
+		var items []string
+		for seq, item := range rb.ConsumeSeq() {
+			seqs = append(seqs, seq)
+			items = append(items, item)
+		}
+		assert.Equal(t, []uint64{3, 4, 5}, seqs, "tracked: %v", tracked)
+		assert.Equal(t, []string{"c", "d", "e"}, items)
+		assert.Equal(t, 0, rb.Length(false))
+
+		// Numbering continues across batch writes
+		_, err := rb.WriteMany([]string{"f", "g"})
+		require.NoError(t, err)
+		for seq, item := range rb.ConsumeSeq() {
+			assert.Equal(t, "f", item)
+			assert.Equal(t, uint64(6), seq)
+			break
+		}
+		assert.Equal(t, 1, rb.Length(false), "stopping early leaves items queued")
+	}
+}
+
+func TestSeqTrackingEnabledLate(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{10, 20})
+	require.NoError(t, err)
+
+	// Queued items are numbered from their position
+	rb.WithSeqTracking(true)
+	require.NoError(t, rb.Write(30))
+	require.NoError(t, rb.Grow(2))
+
+	var seqs []uint64
+	for seq := range rb.ConsumeSeq() {
+		seqs = append(seqs, seq)
+	}
+	assert.Equal(t, []uint64{1, 2, 3}, seqs)
+}
+
+func TestRingBufferForEachDrain(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+
+	var seen []int
+	processed, failed, err := rb.ForEachDrain(func(item int) error {
+		seen = append(seen, item)
+		if item == 3 {
+			return io.ErrShortWrite
+		}
+		return nil
+	}, false)
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+	assert.Equal(t, 2, processed)
+	assert.Equal(t, 3, failed)
+	assert.Equal(t, []int{1, 2, 3}, seen)
+
+	// The failed item is gone, the rest stays queued
+	item, err := rb.GetOne()
+	require.NoError(t, err)
+	assert.Equal(t, 4, item)
+
+	processed, _, err = rb.ForEachDrain(func(int) error { return nil }, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, processed)
+}
+
+func TestRingBufferForEachDrainRequeue(t *testing.T) {
+	rb := ringbuffer.New[int](3)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+
+	processed, failed, err := rb.ForEachDrain(func(item int) error {
+		if item == 2 {
+			return io.ErrShortWrite
+		}
+		return nil
+	}, true)
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, 2, failed)
+
+	items, err := rb.GetN(2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, items)
+
+	// A writer fills the freed slot while fn runs, so the item can't be put back
+	_, err = rb.WriteMany([]int{4, 5, 6})
+	require.NoError(t, err)
+	_, failed, err = rb.ForEachDrain(func(item int) error {
+		require.NoError(t, rb.Write(7))
+		return io.ErrShortWrite
+	}, true)
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+	assert.ErrorIs(t, err, errors.ErrIsFull)
+	assert.Equal(t, 4, failed)
+	require.NoError(t, rb.CheckInvariants())
+}
+
+func TestDrainTo(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true).WithPreserveOnClose(true)
+	require.NotNil(t, rb)
+
+	ch := make(chan int)
+	done := make(chan error, 1)
+	go func() { done <- rb.DrainTo(context.Background(), ch) }()
+
+	// Parks while empty, forwarding items as they are written
+	for i := range 10 {
+		require.NoError(t, rb.Write(i))
+		assert.Equal(t, i, <-ch)
+	}
+
+	// Items queued at close are still forwarded
+	require.NoError(t, rb.Write(10))
+	require.NoError(t, rb.Write(11))
+	require.NoError(t, rb.Close())
+	assert.Equal(t, 10, <-ch)
+	assert.Equal(t, 11, <-ch)
+	assert.ErrorIs(t, <-done, io.EOF)
+}
+
+func TestDrainToCancelKeepsItem(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true)
+	require.NotNil(t, rb)
+	for i := range 3 {
+		require.NoError(t, rb.Write(i))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+	done := make(chan error, 1)
+	go func() { done <- rb.DrainTo(ctx, ch) }()
+
+	assert.Equal(t, 0, <-ch)
+
+	// DrainTo now holds 1, waiting on a channel nobody reads anymore
+	require.Eventually(t, func() bool { return rb.Length(false) == 1 }, time.Second, time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("DrainTo leaked after cancel")
+	}
+
+	items, err := rb.PeekN(2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, items)
+}
+
+func TestDrainToCancelWhileEmpty(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true).WithTimeout(time.Millisecond)
+	require.NotNil(t, rb)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rb.DrainTo(ctx, make(chan int)) }()
+
+	// Read timeouts only restart the wait
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("DrainTo returned early: %v", err)
+	default:
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestDrainToNonBlocking(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+	require.NoError(t, rb.Write(1))
+
+	ch := make(chan int, 1)
+	assert.ErrorIs(t, rb.DrainTo(context.Background(), ch), errors.ErrIsEmpty)
+	assert.Equal(t, 1, <-ch)
+}
+
+func TestDrainToRequeueFails(t *testing.T) {
+	discarded := make(chan int, 1)
+	rb := ringbuffer.New[int](2).WithBlocking(true).WithOnDiscard(func(item int) {
+		discarded <- item
+	})
+	require.NotNil(t, rb)
+	require.NoError(t, rb.Write(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rb.DrainTo(ctx, make(chan int)) }()
+
+	// Fill the slot DrainTo freed, so 1 can't be put back
+	require.Eventually(t, func() bool { return rb.Length(false) == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, rb.Write(2))
+	require.NoError(t, rb.Write(3))
+	cancel()
+
+	err := <-done
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, errors.ErrIsFull)
+	assert.Equal(t, 1, <-discarded)
+}
+
+func TestRequeueWhileDraining(t *testing.T) {
+	errStop := stderrors.New("stop")
+
+	for _, closeGraceful := range []func(rb *ringbuffer.RingBuffer[int]) error{
+		(*ringbuffer.RingBuffer[int]).CloseGraceful,
+		(*ringbuffer.RingBuffer[int]).Shutdown,
+	} {
+		rb := ringbuffer.New[int](4).WithBlocking(true)
+		require.NotNil(t, rb)
+		_, err := rb.WriteMany([]int{1, 2})
+		require.NoError(t, err)
+		require.NoError(t, closeGraceful(rb))
+
+		// ForEachDrain puts the failed item back
+		_, failed, err := rb.ForEachDrain(func(int) error { return errStop }, true)
+		assert.Equal(t, 1, failed)
+		assert.Equal(t, errStop, err)
+
+		// So does DrainTo on cancel
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- rb.DrainTo(ctx, make(chan int)) }()
+		require.Eventually(t, func() bool { return rb.Length(false) == 1 }, time.Second, time.Millisecond)
+		cancel()
+		assert.Equal(t, context.Canceled, <-done)
+
+		var items []int
+		for {
+			item, err := rb.GetOne()
+			if err != nil {
+				assert.ErrorIs(t, err, io.EOF)
+				break
+			}
+			items = append(items, item)
+		}
+		assert.Equal(t, []int{1, 2}, items)
+	}
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitLatencyBuckets(t *testing.T) {
+	clock := newFakeClock()
+	rb := ringbuffer.New[int](1).WithBlocking(true).WithClock(clock).WithTimeout(50 * time.Millisecond)
+	require.NotNil(t, rb)
+	assert.Nil(t, rb.WaitLatencyBuckets(), "disabled by default")
+
+	rb.WithLatencyTracking(true)
+	buckets := rb.WaitLatencyBuckets()
+	require.Len(t, buckets, 8)
+	for i, bucket := range buckets {
+		assert.Zero(t, bucket.Count)
+		if i > 0 {
+			assert.Greater(t, bucket.UpperBound, buckets[i-1].UpperBound)
+		}
+	}
+
+	// A reader times out after 50ms
+	done := make(chan error, 1)
+	go func() {
+		_, err := rb.GetOne()
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 1 }, time.Second, time.Millisecond)
+	clock.Advance(50 * time.Millisecond)
+	assert.ErrorIs(t, <-done, errors.ErrReadTimeout)
+
+	// A writer waits 2ms for a read
+	require.NoError(t, rb.Write(1))
+	go func() { done <- rb.Write(2) }()
+	require.Eventually(t, func() bool { return rb.GetBlockedWriters() == 1 }, time.Second, time.Millisecond)
+	clock.Advance(2 * time.Millisecond)
+	_, err := rb.GetOne()
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+
+	counts := make(map[time.Duration]uint64)
+	for _, bucket := range rb.WaitLatencyBuckets() {
+		counts[bucket.UpperBound] = bucket.Count
+	}
+	assert.Equal(t, uint64(1), counts[100*time.Millisecond])
+	assert.Equal(t, uint64(1), counts[10*time.Millisecond])
+
+	rb.WithLatencyTracking(false)
+	assert.Nil(t, rb.WaitLatencyBuckets())
+}
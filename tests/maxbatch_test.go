@@ -0,0 +1,165 @@
+package test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// maxLockHold runs GetN(n) on a buffer whose cloner makes every item slow to copy,
+// and returns the longest a concurrent Free call had to wait for the lock.
+func maxLockHold(t *testing.T, n, maxBatch int) time.Duration {
+	rb := ringbuffer.New[int](n).WithMaxBatch(maxBatch).WithCloner(func(item int) int {
+		if item%10 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		return item
+	})
+	require.NotNil(t, rb)
+
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i
+	}
+	_, err := rb.WriteMany(items)
+	require.NoError(t, err)
+
+	var (
+		wg      sync.WaitGroup
+		longest time.Duration
+		done    = make(chan struct{})
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			start := time.Now()
+			rb.Free()
+			longest = max(longest, time.Since(start))
+			time.Sleep(100 * time.Microsecond)
+		}
+	}()
+
+	got, err := rb.GetN(n)
+	close(done)
+	wg.Wait()
+
+	require.NoError(t, err)
+	assert.Equal(t, items, got)
+
+	return longest
+}
+
+func TestMaxBatchBoundsLockHold(t *testing.T) {
+	// 200 items take about 20ms to copy out, 10 items about 1ms
+	uncapped := maxLockHold(t, 200, 0)
+	capped := maxLockHold(t, 200, 10)
+
+	t.Logf("max lock wait: uncapped %v, capped %v", uncapped, capped)
+	assert.Greater(t, uncapped, 10*time.Millisecond)
+	assert.Less(t, capped, uncapped/2)
+}
+
+func TestMaxBatch(t *testing.T) {
+	t.Run("WriteMany larger than the buffer", func(t *testing.T) {
+		rb := ringbuffer.New[int](4).WithBlocking(true).WithTimeout(5 * time.Second).WithMaxBatch(2)
+		require.NotNil(t, rb)
+
+		items := make([]int, 10)
+		for i := range items {
+			items[i] = i
+		}
+
+		got := make(chan []int, 1)
+		go func() {
+			var all []int
+			for len(all) < len(items) {
+				batch, err := rb.GetN(1)
+				if !assert.NoError(t, err) {
+					break
+				}
+				all = append(all, batch...)
+			}
+			got <- all
+		}()
+
+		n, err := rb.WriteMany(items)
+		require.NoError(t, err)
+		assert.Equal(t, len(items), n)
+		assert.Equal(t, items, <-got)
+	})
+
+	t.Run("WriteMany stops at the first failing chunk", func(t *testing.T) {
+		rb := ringbuffer.New[int](5).WithMaxBatch(2)
+		require.NotNil(t, rb)
+
+		n, err := rb.WriteMany([]int{1, 2, 3, 4, 5, 6})
+		assert.ErrorIs(t, err, errors.ErrIsFull)
+		assert.Equal(t, 4, n)
+
+		items, err := rb.GetN(4)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3, 4}, items)
+	})
+
+	t.Run("GetN returns the items read before a failing chunk", func(t *testing.T) {
+		rb := ringbuffer.New[int](8).WithMaxBatch(2)
+		require.NotNil(t, rb)
+
+		_, err := rb.WriteMany([]int{1, 2, 3})
+		require.NoError(t, err)
+
+		items, err := rb.GetN(4)
+		assert.ErrorIs(t, err, errors.ErrIsEmpty)
+		assert.Equal(t, []int{1, 2}, items)
+
+		_, err = rb.GetN(9)
+		assert.ErrorIs(t, err, errors.ErrInvalidLength)
+	})
+
+	t.Run("GetN on an unbounded buffer accepts more than the capacity", func(t *testing.T) {
+		rb := ringbuffer.NewUnbounded[int]().WithBlocking(true).WithTimeout(5 * time.Second).WithMaxBatch(2)
+		require.NotNil(t, rb)
+
+		items := make([]int, 3*rb.Capacity())
+		for i := range items {
+			items[i] = i
+		}
+
+		// The buffer grows as the writes arrive, after GetN started waiting
+		go func() {
+			for _, item := range items {
+				assert.NoError(t, rb.Write(item))
+			}
+		}()
+
+		got, err := rb.GetN(len(items))
+		require.NoError(t, err)
+		assert.Equal(t, items, got)
+	})
+
+	t.Run("Batches within the cap stay atomic", func(t *testing.T) {
+		rb := ringbuffer.New[int](3).WithMaxBatch(3)
+		require.NotNil(t, rb)
+
+		require.NoError(t, rb.Write(0))
+		n, err := rb.WriteMany([]int{1, 2, 3})
+		assert.ErrorIs(t, err, errors.ErrIsFull)
+		assert.Equal(t, 0, n)
+
+		_, err = rb.GetN(2)
+		assert.ErrorIs(t, err, errors.ErrIsEmpty)
+		assert.Equal(t, 1, rb.Length(false))
+	})
+}
@@ -0,0 +1,179 @@
+package test
+
+import (
+	"io"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMPMCBasic(t *testing.T) {
+	assert.Nil(t, ringbuffer.NewMPMC[int](0))
+	assert.Equal(t, 2, ringbuffer.NewMPMC[int](1).Capacity())
+
+	m := ringbuffer.NewMPMC[int](3)
+	require.NotNil(t, m)
+	assert.Equal(t, 4, m.Capacity())
+
+	_, err := m.TryRead()
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+
+	for i := range 4 {
+		require.NoError(t, m.TryWrite(i))
+	}
+	assert.ErrorIs(t, m.TryWrite(4), errors.ErrIsFull)
+	assert.ErrorIs(t, m.Write(4), errors.ErrIsFull)
+	assert.Equal(t, 4, m.Length())
+
+	// Several laps around the ring keep FIFO order
+	for i := range 12 {
+		item, err := m.Read()
+		require.NoError(t, err)
+		assert.Equal(t, i, item)
+		require.NoError(t, m.TryWrite(i+4))
+	}
+	assert.Equal(t, 4, m.Length())
+}
+
+func TestMPMCBlocking(t *testing.T) {
+	m := ringbuffer.NewMPMC[int](2).WithBlocking(true)
+	require.NotNil(t, m)
+
+	read := make(chan int, 1)
+	go func() {
+		item, err := m.Read()
+		assert.NoError(t, err)
+		read <- item
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, m.Write(1))
+	select {
+	case item := <-read:
+		assert.Equal(t, 1, item)
+	case <-time.After(time.Second):
+		t.Fatal("Read should return once an item is written")
+	}
+
+	require.NoError(t, m.Write(2))
+	require.NoError(t, m.Write(3))
+	written := make(chan error, 1)
+	go func() {
+		written <- m.Write(4)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	item, err := m.Read()
+	require.NoError(t, err)
+	assert.Equal(t, 2, item)
+	select {
+	case err := <-written:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Write should return once a slot is free")
+	}
+}
+
+func TestMPMCClose(t *testing.T) {
+	m := ringbuffer.NewMPMC[int](2).WithBlocking(true)
+	require.NotNil(t, m)
+
+	require.NoError(t, m.Write(1))
+	require.NoError(t, m.Write(2))
+
+	written := make(chan error, 1)
+	go func() {
+		written <- m.Write(3)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, m.Close())
+	select {
+	case err := <-written:
+		assert.ErrorIs(t, err, io.EOF)
+	case <-time.After(time.Second):
+		t.Fatal("Close should wake blocked writers")
+	}
+
+	// Queued items drain before io.EOF
+	for _, want := range []int{1, 2} {
+		item, err := m.Read()
+		require.NoError(t, err)
+		assert.Equal(t, want, item)
+	}
+	_, err := m.Read()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.ErrorIs(t, m.TryWrite(4), io.EOF)
+}
+
+func TestMPMCConcurrent(t *testing.T) {
+	const producers, consumers, perProducer = 8, 8, 2000
+
+	for _, block := range []bool{false, true} {
+		m := ringbuffer.NewMPMC[int](16).WithBlocking(block)
+		require.NotNil(t, m)
+
+		var produced sync.WaitGroup
+		for p := range producers {
+			produced.Add(1)
+			go func() {
+				defer produced.Done()
+				for i := 0; i < perProducer; {
+					if m.Write(p*perProducer+i) != nil {
+						runtime.Gosched()
+						continue
+					}
+					i++
+				}
+			}()
+		}
+
+		seen := make([][]int, consumers)
+		var consumed sync.WaitGroup
+		for c := range consumers {
+			consumed.Add(1)
+			go func() {
+				defer consumed.Done()
+				for {
+					item, err := m.Read()
+					if err == io.EOF {
+						return
+					}
+					if err != nil {
+						runtime.Gosched()
+						continue
+					}
+					seen[c] = append(seen[c], item)
+				}
+			}()
+		}
+
+		produced.Wait()
+		require.NoError(t, m.Close())
+		consumed.Wait()
+
+		// Every item is read exactly once, in each producer's order per consumer
+		count := make([]int, producers*perProducer)
+		for _, items := range seen {
+			last := make([]int, producers)
+			for i := range last {
+				last[i] = -1
+			}
+			for _, item := range items {
+				count[item]++
+				p := item / perProducer
+				assert.Greater(t, item, last[p], "block %v", block)
+				last[p] = item
+			}
+		}
+		for item, n := range count {
+			require.Equal(t, 1, n, "item %d, block %v", item, block)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverwriteWrite(t *testing.T) {
+	rb := ringbuffer.New[int](3).WithOverwrite(true)
+	require.NotNil(t, rb)
+
+	var discarded []int
+	rb.WithOnDiscard(func(item int) { discarded = append(discarded, item) })
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, rb.Write(i))
+	}
+
+	assert.Equal(t, []int{1, 2}, discarded)
+	items, err := rb.GetN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, items)
+}
+
+func TestOverwriteWriteManyBatchEviction(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithOverwrite(true)
+	require.NotNil(t, rb)
+
+	var batches [][]int
+	rb.WithOnDiscardMany(func(items []int) {
+		batches = append(batches, append([]int(nil), items...))
+	})
+
+	_, err := rb.WriteMany([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+
+	// Needs 2 evictions, done in a single step
+	n, err := rb.WriteMany([]int{5, 6, 7})
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, [][]int{{1, 2}}, batches)
+
+	// Larger than capacity: everything queued plus the head of the batch is discarded
+	batches = nil
+	n, err = rb.WriteMany([]int{10, 11, 12, 13, 14, 15, 16})
+	require.NoError(t, err)
+	assert.Equal(t, 7, n)
+
+	var all []int
+	for _, b := range batches {
+		all = append(all, b...)
+	}
+	assert.ElementsMatch(t, []int{10, 11, 3, 4, 5, 6, 7}, all)
+
+	items, err := rb.GetN(5)
+	require.NoError(t, err)
+	assert.Equal(t, []int{12, 13, 14, 15, 16}, items)
+}
+
+func TestOverwriteWriteManyAtWrap(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithOverwrite(true)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+
+	start, wrapAt, err := rb.WriteManyAt([]int{4, 5, 6})
+	require.NoError(t, err)
+	assert.Equal(t, 3, start)
+	assert.Equal(t, 1, wrapAt)
+
+	items, err := rb.GetN(4)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5, 6}, items)
+
+	// A batch larger than the buffer evicts everything and starts over
+	require.NoError(t, rb.Write(7))
+	start, wrapAt, err = rb.WriteManyAt([]int{8, 9, 10, 11, 12, 13})
+	require.NoError(t, err)
+	assert.Equal(t, 0, start)
+	assert.Equal(t, -1, wrapAt)
+
+	items, err = rb.GetN(4)
+	require.NoError(t, err)
+	assert.Equal(t, []int{10, 11, 12, 13}, items)
+}
+
+func TestOverwriteGetOneSeq(t *testing.T) {
+	rb := ringbuffer.New[int](3).WithOverwrite(true)
+	require.NotNil(t, rb)
+
+	// 1 2 3, then 4 and 5 overwrite 1 and 2
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, rb.Write(i))
+	}
+
+	item, seq, gap, err := rb.GetOneSeq()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, item)
+	assert.Equal(t, uint64(3), seq)
+	assert.Equal(t, 2, gap)
+
+	item, seq, gap, err = rb.GetOneSeq()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, item)
+	assert.Equal(t, uint64(4), seq)
+	assert.Equal(t, 0, gap)
+
+	// A batch larger than the buffer: 6 7 are skipped, 5 is evicted
+	_, err = rb.WriteMany([]int{6, 7, 8, 9, 10})
+	require.NoError(t, err)
+
+	item, seq, gap, err = rb.GetOneSeq()
+	assert.NoError(t, err)
+	assert.Equal(t, 8, item)
+	assert.Equal(t, uint64(8), seq)
+	assert.Equal(t, 3, gap)
+
+	// Items consumed by other reads are not gaps
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+	item, seq, gap, err = rb.GetOneSeq()
+	assert.NoError(t, err)
+	assert.Equal(t, 10, item)
+	assert.Equal(t, uint64(10), seq)
+	assert.Equal(t, 0, gap)
+
+	_, _, _, err = rb.GetOneSeq()
+	assert.Error(t, err)
+}
+
+type prioritized struct {
+	name     string
+	priority int
+}
+
+// lowestPriorityFirst evicts the lowest priority item, the oldest one among equals.
+type lowestPriorityFirst struct{}
+
+func (lowestPriorityFirst) Evict(part1, part2 []prioritized) int {
+	items := slices.Concat(part1, part2)
+
+	victim := 0
+	for i, item := range items {
+		if item.priority < items[victim].priority {
+			victim = i
+		}
+	}
+	return victim
+}
+
+func TestOverwriteEvictionPolicy(t *testing.T) {
+	rb := ringbuffer.New[prioritized](3).WithOverwrite(true).WithEvictionPolicy(lowestPriorityFirst{})
+	require.NotNil(t, rb)
+
+	var discarded []string
+	rb.WithOnDiscard(func(item prioritized) { discarded = append(discarded, item.name) })
+
+	// Wrap the queue around the buffer end first
+	_, err := rb.WriteMany([]prioritized{{"x", 0}, {"y", 0}})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+
+	_, err = rb.WriteMany([]prioritized{{"a", 5}, {"b", 1}, {"c", 3}})
+	require.NoError(t, err)
+
+	require.NoError(t, rb.Write(prioritized{"d", 4}))
+	assert.Equal(t, []string{"b"}, discarded)
+
+	require.NoError(t, rb.Write(prioritized{"e", 9}))
+	assert.Equal(t, []string{"b", "c"}, discarded)
+
+	_, err = rb.WriteMany([]prioritized{{"f", 2}, {"g", 7}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c", "d", "a"}, discarded)
+
+	items, err := rb.GetN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []prioritized{{"e", 9}, {"f", 2}, {"g", 7}}, items)
+
+	// Out of range indexes and a nil policy fall back to drop-oldest
+	rb.WithEvictionPolicy(ringbuffer.EvictionPolicyFunc[prioritized](func(part1, part2 []prioritized) int {
+		return -1
+	}))
+	_, err = rb.WriteMany([]prioritized{{"h", 0}, {"i", 0}, {"j", 0}, {"k", 0}})
+	require.NoError(t, err)
+	rb.WithEvictionPolicy(nil)
+	require.NoError(t, rb.Write(prioritized{"l", 0}))
+	assert.Equal(t, []string{"b", "c", "d", "a", "h", "i"}, discarded)
+}
+
+func TestCoalesceSingleSlot(t *testing.T) {
+	var replaced []int
+	rb := ringbuffer.New[int](1).WithBlocking(true).WithCoalesce(true).
+		WithOnDiscard(func(item int) { replaced = append(replaced, item) })
+	require.NotNil(t, rb)
+
+	// Never blocks, the latest value wins
+	for i := range 5 {
+		require.NoError(t, rb.Write(i))
+	}
+	assert.Equal(t, []int{0, 1, 2, 3}, replaced)
+
+	item, err := rb.GetOne()
+	require.NoError(t, err)
+	assert.Equal(t, 4, item)
+	assert.True(t, rb.IsEmpty())
+}
+
+func TestCoalesceReplacesNewest(t *testing.T) {
+	rb := ringbuffer.New[int](3).WithOverwrite(true).WithCoalesce(true).WithSeqTracking(true)
+	require.NotNil(t, rb)
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, rb.Write(i))
+	}
+
+	// Overwrite would have kept 3, 4, 5: coalescing keeps the oldest and replaces the newest
+	items, err := rb.PeekN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 5}, items)
+
+	// The latest value takes over the sequence number of the item it replaced
+	for want := uint64(1); want <= 3; want++ {
+		_, seq, _, err := rb.GetOneSeq()
+		require.NoError(t, err)
+		assert.Equal(t, want, seq)
+	}
+
+	// Batch writes don't coalesce
+	n, err := rb.WriteMany([]int{6, 7, 8, 9})
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	items, err = rb.GetN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{7, 8, 9}, items)
+}
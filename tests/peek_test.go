@@ -1,7 +1,10 @@
 package test
 
 import (
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/AlexsanderHamir/ringbuffer"
 	"github.com/AlexsanderHamir/ringbuffer/errors"
@@ -148,3 +151,248 @@ func TestRingBufferPeekViewOperations(t *testing.T) {
 	assert.Nil(t, part1)
 	assert.Nil(t, part2)
 }
+
+func TestRingBufferCloner(t *testing.T) {
+	rb := ringbuffer.New[*TestValue](4).WithCloner(func(v *TestValue) *TestValue {
+		c := *v
+		return &c
+	})
+	require.NotNil(t, rb)
+
+	original := &TestValue{value: 1}
+	require.NoError(t, rb.Write(original))
+	require.NoError(t, rb.Write(&TestValue{value: 2}))
+
+	peeked, err := rb.PeekOne()
+	require.NoError(t, err)
+	assert.NotSame(t, original, peeked)
+	peeked.value = 100
+
+	many, err := rb.PeekN(2)
+	require.NoError(t, err)
+	assert.NotSame(t, original, many[0])
+	assert.Equal(t, 1, many[0].value)
+
+	// Views are never cloned
+	part1, _, err := rb.PeekNView(1)
+	require.NoError(t, err)
+	assert.Same(t, original, part1[0])
+
+	items, err := rb.GetN(2)
+	require.NoError(t, err)
+	assert.NotSame(t, original, items[0])
+	assert.Equal(t, 1, items[0].value)
+	assert.Equal(t, 2, items[1].value)
+}
+
+func TestRingBufferPeekOneBlocking(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true)
+	require.NotNil(t, rb)
+	defer rb.Close()
+
+	start := time.Now()
+	_, err := rb.PeekOneBlocking(30 * time.Millisecond)
+	assert.ErrorIs(t, err, errors.ErrReadTimeout)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	done := make(chan int)
+	go func() {
+		item, err := rb.PeekOneBlocking(time.Second)
+		assert.NoError(t, err)
+		done <- item
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, rb.Write(42))
+
+	select {
+	case item := <-done:
+		assert.Equal(t, 42, item)
+	case <-time.After(time.Second):
+		t.Fatal("PeekOneBlocking should have been woken by the write")
+	}
+
+	// The item is still there
+	assert.Equal(t, 1, rb.Length(false))
+
+	nonBlocking := ringbuffer.New[int](4)
+	_, err = nonBlocking.PeekOneBlocking(time.Second)
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+}
+
+func TestRingBufferSwapHead(t *testing.T) {
+	rb := ringbuffer.New[int](3)
+	require.NotNil(t, rb)
+
+	_, err := rb.SwapHead(1)
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+
+	// Put the head at the end of the buffer
+	_, err = rb.WriteMany([]int{0, 0, 10})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+	require.NoError(t, rb.Write(20))
+
+	old, err := rb.SwapHead(11)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, old)
+	assert.Equal(t, 2, rb.Length(false))
+
+	item, err := rb.GetOne()
+	assert.NoError(t, err)
+	assert.Equal(t, 11, item)
+
+	item, err = rb.GetOne()
+	assert.NoError(t, err)
+	assert.Equal(t, 20, item)
+}
+
+func TestRingBufferPeekAllFunc(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+
+	// Empty buffer gets empty views
+	err := rb.PeekAllFunc(func(part1, part2 []int) error {
+		assert.Empty(t, part1)
+		assert.Empty(t, part2)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// Wrap the queued items around the buffer end
+	_, err = rb.WriteMany([]int{0, 0, 1, 2})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+	_, err = rb.WriteMany([]int{3, 4})
+	require.NoError(t, err)
+
+	err = rb.PeekAllFunc(func(part1, part2 []int) error {
+		assert.Equal(t, []int{1, 2}, part1)
+		assert.Equal(t, []int{3, 4}, part2)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// fn's error is returned and nothing is consumed
+	sentinel := fmt.Errorf("stop")
+	err = rb.PeekAllFunc(func(part1, part2 []int) error {
+		return sentinel
+	})
+	assert.ErrorIs(t, err, sentinel)
+
+	items, err := rb.GetN(4)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, items)
+}
+
+func TestRingBufferPeekUpToN(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+
+	assert.Empty(t, rb.PeekUpToN(3))
+	assert.Empty(t, rb.PeekUpToN(0))
+
+	// Items wrap around the buffer end
+	_, err := rb.WriteMany([]int{0, 0, 1, 2})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+	_, err = rb.WriteMany([]int{3})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 2}, rb.PeekUpToN(2))
+	assert.Equal(t, []int{1, 2, 3}, rb.PeekUpToN(10))
+
+	// Copies, never consumes
+	peeked := rb.PeekUpToN(1)
+	peeked[0] = 100
+	items, err := rb.GetN(3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, items)
+
+	var nilBuffer *ringbuffer.RingBuffer[int]
+	assert.Empty(t, nilBuffer.PeekUpToN(1))
+}
+
+func TestRingBufferRWMutexConcurrentPeeks(t *testing.T) {
+	for _, block := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Blocking_%v", block), func(t *testing.T) {
+			rb := ringbuffer.New[int](64).WithBlocking(block).WithRWMutex(true)
+			require.NotNil(t, rb)
+
+			var wg sync.WaitGroup
+			for range 4 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range 1000 {
+						if item, err := rb.PeekOne(); err == nil {
+							assert.GreaterOrEqual(t, item, 0)
+						}
+						rb.PeekN(2)
+						rb.Inspect(true)
+					}
+				}()
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range 1000 {
+					if !assert.NoError(t, rb.Write(i)) {
+						return
+					}
+					if _, err := rb.GetOne(); !assert.NoError(t, err) {
+						return
+					}
+				}
+			}()
+
+			wg.Wait()
+			assert.True(t, rb.IsEmpty())
+		})
+	}
+}
+
+func TestRingBufferPeekRecentN(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	_, err := rb.PeekRecentN(0)
+	assert.ErrorIs(t, err, errors.ErrInvalidLength)
+	_, err = rb.PeekRecentN(1)
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+
+	// Move the positions around so the newest items wrap across the buffer end
+	_, err = rb.WriteMany([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+	_, err = rb.GetN(3)
+	require.NoError(t, err)
+	for i := 5; i <= 8; i++ {
+		require.NoError(t, rb.Write(i))
+	}
+
+	items, err := rb.PeekRecentN(5)
+	require.NoError(t, err)
+	assert.Equal(t, []int{8, 7, 6, 5, 4}, items)
+
+	items, err = rb.PeekRecentN(2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{8, 7}, items)
+
+	_, err = rb.PeekRecentN(6)
+	assert.ErrorIs(t, err, errors.ErrTooMuchDataToPeek)
+
+	// Owned copy, nothing consumed
+	items[0] = 100
+	assert.Equal(t, 5, rb.Length(false))
+	item, err := rb.GetOne()
+	require.NoError(t, err)
+	assert.Equal(t, 4, item)
+
+	items, err = rb.PeekRecentN(4)
+	require.NoError(t, err)
+	assert.Equal(t, []int{8, 7, 6, 5}, items)
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolLimitsConcurrency(t *testing.T) {
+	const limit = 3
+
+	// A pool of tokens used as a counting semaphore
+	sem := ringbuffer.NewPool(make([]struct{}, limit))
+	require.NotNil(t, sem)
+
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			token, err := sem.Acquire(context.Background())
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer sem.Release(token)
+
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	assert.LessOrEqual(t, peak.Load(), int32(limit))
+	assert.Equal(t, limit, sem.Available())
+}
+
+func TestPoolAcquireRelease(t *testing.T) {
+	pool := ringbuffer.NewPool([]string{"conn-1", "conn-2"})
+	require.NotNil(t, pool)
+	assert.Equal(t, 2, pool.Capacity())
+
+	ctx := context.Background()
+	first, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	second, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"conn-1", "conn-2"}, []string{first, second})
+	assert.Equal(t, 0, pool.Available())
+
+	// Exhausted pool waits for a release
+	got := make(chan string, 1)
+	go func() {
+		item, err := pool.Acquire(ctx)
+		assert.NoError(t, err)
+		got <- item
+	}()
+	select {
+	case <-got:
+		t.Fatal("Acquire should block while the pool is exhausted")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	require.NoError(t, pool.Release(first))
+	select {
+	case item := <-got:
+		assert.Equal(t, "conn-1", item)
+	case <-time.After(time.Second):
+		t.Fatal("Acquire should have been woken by the release")
+	}
+
+	// Releasing more than acquired overflows the pool
+	require.NoError(t, pool.Release(second))
+	require.NoError(t, pool.Release("conn-1"))
+	assert.ErrorIs(t, pool.Release("conn-3"), errors.ErrIsFull)
+
+	assert.Nil(t, ringbuffer.NewPool[int](nil))
+}
+
+func TestPoolAcquireContext(t *testing.T) {
+	pool := ringbuffer.NewPool([]int{1})
+	require.NotNil(t, pool)
+
+	_, err := pool.Acquire(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = pool.Acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Close wakes waiters with io.EOF
+	done := make(chan error, 1)
+	go func() {
+		_, err := pool.Acquire(context.Background())
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, pool.Close())
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, io.EOF)
+	case <-time.After(time.Second):
+		t.Fatal("Close should wake waiting Acquire")
+	}
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingBufferPrefetch(t *testing.T) {
+	var calls atomic.Int32
+	next := 0
+	rb := ringbuffer.New[int](4).WithPrefetch(0, func() []int {
+		calls.Add(1)
+		items := []int{next, next + 1, next + 2}
+		next += 3
+		return items
+	})
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{-2, -1})
+	require.NoError(t, err)
+
+	// Still above the threshold
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+	rb.Wait()
+	assert.Equal(t, int32(0), calls.Load())
+
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+	rb.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+
+	items, err := rb.GetN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, items)
+	rb.Wait()
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, 3, rb.Length(false))
+}
+
+func TestRingBufferPrefetchDropsOverflow(t *testing.T) {
+	var discarded []int
+	rb := ringbuffer.New[int](2).
+		WithOnDiscard(func(item int) { discarded = append(discarded, item) }).
+		WithPrefetch(0, func() []int { return []int{1, 2, 3} })
+	require.NotNil(t, rb)
+
+	require.NoError(t, rb.Write(0))
+	_, err := rb.GetOne()
+	require.NoError(t, err)
+	rb.Wait()
+
+	items, err := rb.GetN(2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, items)
+	rb.Wait()
+	assert.Equal(t, []int{3, 3}, discarded)
+
+	// Never written, so neither dropped nor reported as a sequence gap
+	assert.Equal(t, uint64(0), rb.Stats().Dropped)
+	_, _, gap, err := rb.GetOneSeq()
+	require.NoError(t, err)
+	assert.Equal(t, 0, gap)
+}
+
+func TestRingBufferPrefetchSingleFlight(t *testing.T) {
+	var running, overlaps atomic.Int32
+	release := make(chan struct{})
+	rb := ringbuffer.New[int](8).WithPrefetch(8, func() []int {
+		if running.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		defer running.Add(-1)
+		<-release
+		return []int{1}
+	})
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+	for range 4 {
+		_, err := rb.GetOne()
+		require.NoError(t, err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int32(1), running.Load())
+	close(release)
+	rb.Wait()
+	assert.Equal(t, int32(0), overlaps.Load())
+	assert.Equal(t, 1, rb.Length(false))
+}
+
+func TestRingBufferPrefetchClosed(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithPrefetch(0, func() []int {
+		return []int{1}
+	})
+	require.NotNil(t, rb)
+
+	require.NoError(t, rb.Write(1))
+	require.NoError(t, rb.CloseGraceful())
+	_, err := rb.GetOne()
+	require.NoError(t, err)
+	rb.Wait()
+	assert.Equal(t, 0, rb.Length(false))
+}
@@ -0,0 +1,189 @@
+package test
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservedCapacity(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithReservedCapacity(2)
+	require.NotNil(t, rb)
+
+	// Normal writes stop at size - reserved
+	_, err := rb.WriteMany([]int{1, 2})
+	require.NoError(t, err)
+	require.NoError(t, rb.Write(3))
+	assert.ErrorIs(t, rb.Write(4), errors.ErrIsFull)
+	_, err = rb.WriteMany([]int{4})
+	assert.ErrorIs(t, err, errors.ErrIsFull)
+	assert.True(t, rb.WouldBlockWrite(1))
+
+	// Priority writes use the reserve, up to the full capacity
+	require.NoError(t, rb.WritePriority(4))
+	require.NoError(t, rb.WritePriority(5))
+	assert.ErrorIs(t, rb.WritePriority(6), errors.ErrIsFull)
+
+	items, err := rb.GetN(5)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, items)
+}
+
+func TestReservedCapacityBlocking(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true).WithTimeout(20 * time.Millisecond).WithReservedCapacity(1)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+
+	// Normal writes block at size - reserved and time out
+	assert.ErrorIs(t, rb.Write(4), context.DeadlineExceeded)
+	require.NoError(t, rb.WritePriority(4))
+
+	// A blocked priority writer gets the freed slot even with a normal writer blocked too
+	rb.WithTimeout(5 * time.Second)
+	normal := make(chan error, 1)
+	go func() { normal <- rb.Write(10) }()
+	require.Eventually(t, func() bool { return rb.GetBlockedWriters() == 1 }, time.Second, time.Millisecond)
+
+	priority := make(chan error, 1)
+	go func() { priority <- rb.WritePriority(5) }()
+	require.Eventually(t, func() bool { return rb.GetBlockedWriters() == 2 }, time.Second, time.Millisecond)
+
+	item, err := rb.GetOne()
+	require.NoError(t, err)
+	assert.Equal(t, 1, item)
+
+	select {
+	case err := <-priority:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("priority write should have used the freed slot")
+	}
+
+	// The normal writer is still waiting for room outside the reserve
+	select {
+	case <-normal:
+		t.Fatal("normal write must not use the reserved slot")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	items, err := rb.GetN(2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, items)
+	assert.NoError(t, <-normal)
+}
+
+func TestNewPriority(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	assert.Nil(t, ringbuffer.NewPriority[int](0, less))
+	assert.Nil(t, ringbuffer.NewPriority[int](4, nil))
+
+	rng := rand.New(rand.NewSource(1))
+	for round := range 200 {
+		rb := ringbuffer.NewPriority[int](16, less)
+		require.NotNil(t, rb)
+
+		// Interleave writes and reads so the queued items wrap around the ring
+		var want []int
+		for range rng.Intn(40) {
+			if len(want) > 0 && rng.Intn(3) == 0 {
+				item, err := rb.GetOne()
+				require.NoError(t, err)
+				assert.Equal(t, want[0], item, "round %d", round)
+				want = want[1:]
+				continue
+			}
+
+			items := make([]int, rng.Intn(min(4, 16-len(want))+1))
+			for i := range items {
+				items[i] = rng.Intn(50)
+			}
+			_, err := rb.WriteMany(items)
+			require.NoError(t, err)
+
+			want = append(want, items...)
+			sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+			require.NoError(t, rb.CheckInvariants())
+		}
+
+		got := rb.PeekUpToN(16)
+		if len(want) == 0 {
+			assert.Empty(t, got)
+			continue
+		}
+		assert.Equal(t, want, got, "round %d", round)
+	}
+}
+
+func TestNewPriorityStable(t *testing.T) {
+	type job struct{ prio, id int }
+	rb := ringbuffer.NewPriority[job](8, func(a, b job) bool { return a.prio < b.prio })
+	require.NotNil(t, rb)
+
+	for id, prio := range []int{2, 1, 2, 1, 0} {
+		require.NoError(t, rb.Write(job{prio, id}))
+	}
+
+	items, err := rb.GetN(5)
+	require.NoError(t, err)
+	assert.Equal(t, []job{{0, 4}, {1, 1}, {1, 3}, {2, 0}, {2, 2}}, items)
+}
+
+func TestNewPriorityFull(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	t.Run("Reject", func(t *testing.T) {
+		rb := ringbuffer.NewPriority[int](3, less)
+		_, err := rb.WriteMany([]int{3, 1, 2})
+		require.NoError(t, err)
+		assert.ErrorIs(t, rb.Write(0), errors.ErrIsFull)
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		rb := ringbuffer.NewPriority[int](3, less).WithBlocking(true).WithTimeout(5 * time.Second)
+		_, err := rb.WriteMany([]int{3, 1, 2})
+		require.NoError(t, err)
+
+		done := make(chan error, 1)
+		go func() { done <- rb.Write(0) }()
+		require.Eventually(t, func() bool { return rb.GetBlockedWriters() == 1 }, time.Second, time.Millisecond)
+
+		item, err := rb.GetOne()
+		require.NoError(t, err)
+		assert.Equal(t, 1, item)
+		require.NoError(t, <-done)
+
+		items, err := rb.GetN(3)
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 2, 3}, items)
+	})
+
+	t.Run("EvictLowest", func(t *testing.T) {
+		var dropped []int
+		rb := ringbuffer.NewPriority[int](3, less).WithOverwrite(true).
+			WithOnDiscard(func(item int) { dropped = append(dropped, item) })
+		_, err := rb.WriteMany([]int{3, 1, 2})
+		require.NoError(t, err)
+
+		// Ranks higher than 3, which is dropped
+		require.NoError(t, rb.Write(0))
+		// Ranks lowest, so it is dropped itself
+		require.NoError(t, rb.Write(9))
+		// Only the two best of a batch make it
+		_, err = rb.WriteMany([]int{5, -1, -2, 7})
+		require.NoError(t, err)
+
+		items, err := rb.GetN(3)
+		require.NoError(t, err)
+		assert.Equal(t, []int{-2, -1, 0}, items)
+		assert.Equal(t, []int{3, 9, 5, 2, 1, 7}, dropped)
+	})
+}
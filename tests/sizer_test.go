@@ -0,0 +1,147 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func byteLen(b []byte) int { return len(b) }
+
+func TestRingBufferSizerTracksBytes(t *testing.T) {
+	rb := ringbuffer.New[[]byte](4).WithSizer(byteLen)
+	require.NotNil(t, rb)
+
+	require.NoError(t, rb.Write(make([]byte, 10)))
+	_, err := rb.WriteMany([][]byte{make([]byte, 5), make([]byte, 3)})
+	require.NoError(t, err)
+	assert.Equal(t, 18, rb.LengthBytes())
+
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+	assert.Equal(t, 8, rb.LengthBytes())
+
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+	assert.Equal(t, 0, rb.LengthBytes())
+
+	require.NoError(t, rb.Write(make([]byte, 7)))
+	rb.Flush()
+	assert.Equal(t, 0, rb.LengthBytes())
+	require.NoError(t, rb.CheckInvariants())
+
+	// Items queued before the sizer is set are measured
+	late := ringbuffer.New[[]byte](4)
+	require.NoError(t, late.Write(make([]byte, 6)))
+	assert.Equal(t, 0, late.LengthBytes())
+	late.WithSizer(byteLen)
+	assert.Equal(t, 6, late.LengthBytes())
+	assert.Equal(t, -1, late.FreeBytes())
+}
+
+func TestRingBufferWriteBytesBounded(t *testing.T) {
+	rb := ringbuffer.New[[]byte](8).WithSizer(byteLen).WithByteBudget(16)
+	require.NotNil(t, rb)
+
+	require.NoError(t, rb.WriteBytesBounded(make([]byte, 10)))
+	require.NoError(t, rb.WriteBytesBounded(make([]byte, 6)))
+	assert.Equal(t, 0, rb.FreeBytes())
+
+	// Slots are free, bytes are not
+	assert.ErrorIs(t, rb.WriteBytesBounded(make([]byte, 1)), errors.ErrIsFull)
+	assert.ErrorIs(t, rb.WriteBytesBounded(make([]byte, 17)), errors.ErrTooMuchDataToWrite)
+
+	// Other writes ignore the budget
+	require.NoError(t, rb.Write(make([]byte, 4)))
+	assert.Equal(t, 20, rb.LengthBytes())
+
+	_, err := rb.GetN(2)
+	require.NoError(t, err)
+	assert.Equal(t, 12, rb.FreeBytes())
+	require.NoError(t, rb.WriteBytesBounded(make([]byte, 12)))
+	require.NoError(t, rb.CheckInvariants())
+}
+
+func TestRingBufferWriteBytesBoundedCoalesce(t *testing.T) {
+	rb := ringbuffer.New[[]byte](2).WithSizer(byteLen).WithByteBudget(10).WithCoalesce(true)
+	require.NotNil(t, rb)
+
+	require.NoError(t, rb.WriteBytesBounded([]byte("aaaa")))
+	require.NoError(t, rb.WriteBytesBounded([]byte("bb")))
+
+	// Replacing the newest item frees its bytes
+	require.NoError(t, rb.WriteBytesBounded([]byte("cccccc")))
+	assert.Equal(t, 10, rb.LengthBytes())
+
+	// The replacement doesn't fit the budget, so the full buffer rejects it
+	assert.ErrorIs(t, rb.WriteBytesBounded([]byte("ddddddd")), errors.ErrIsFull)
+	assert.Equal(t, 10, rb.LengthBytes())
+
+	items, err := rb.PeekN(2)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("aaaa"), []byte("cccccc")}, items)
+	require.NoError(t, rb.CheckInvariants())
+}
+
+func TestRingBufferWriteBytesBoundedOverwrite(t *testing.T) {
+	var discarded []int
+	rb := ringbuffer.New[[]byte](8).WithSizer(byteLen).WithByteBudget(10).WithOverwrite(true).
+		WithOnDiscard(func(item []byte) { discarded = append(discarded, len(item)) })
+	require.NotNil(t, rb)
+
+	for _, n := range []int{3, 3, 3} {
+		require.NoError(t, rb.WriteBytesBounded(make([]byte, n)))
+	}
+
+	// Evicts the two oldest items to fit 5 more bytes
+	require.NoError(t, rb.WriteBytesBounded(make([]byte, 5)))
+	assert.Equal(t, []int{3, 3}, discarded)
+	assert.Equal(t, 8, rb.LengthBytes())
+	assert.Equal(t, 2, rb.Length(false))
+	require.NoError(t, rb.CheckInvariants())
+}
+
+func TestRingBufferWriteBytesBoundedBlocking(t *testing.T) {
+	rb := ringbuffer.New[[]byte](8).WithSizer(byteLen).WithByteBudget(10).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	require.NoError(t, rb.WriteBytesBounded(make([]byte, 8)))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- rb.WriteBytesBounded(make([]byte, 5))
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("write didn't wait for bytes to free up: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	_, err := rb.GetOne()
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+	assert.Equal(t, 5, rb.LengthBytes())
+}
+
+func TestRingBufferSizerPriority(t *testing.T) {
+	rb := ringbuffer.NewPriority(3, func(a, b []byte) bool { return len(a) > len(b) }).
+		WithSizer(byteLen).WithOverwrite(true)
+	require.NotNil(t, rb)
+
+	// The smallest item is dropped once the buffer is full
+	for _, n := range []int{2, 5, 1, 4} {
+		require.NoError(t, rb.Write(make([]byte, n)))
+		require.NoError(t, rb.CheckInvariants())
+	}
+	assert.Equal(t, 11, rb.LengthBytes())
+
+	item, err := rb.GetOne()
+	require.NoError(t, err)
+	assert.Len(t, item, 5)
+	assert.Equal(t, 6, rb.LengthBytes())
+}
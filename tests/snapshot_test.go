@@ -0,0 +1,54 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotPublishing(t *testing.T) {
+	clock := newFakeClock()
+	rb := ringbuffer.New[int](4).WithClock(clock)
+	require.NotNil(t, rb)
+	assert.Nil(t, rb.LatestSnapshot())
+
+	_, err := rb.WriteMany([]int{1, 2})
+	require.NoError(t, err)
+
+	rb.EnableSnapshotPublishing(time.Second)
+	assert.Equal(t, []int{1, 2}, rb.LatestSnapshot())
+
+	// Lags until the next interval
+	require.NoError(t, rb.Write(3))
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, rb.LatestSnapshot())
+
+	clock.Advance(time.Second)
+	assert.Equal(t, []int{2, 3}, rb.LatestSnapshot())
+
+	// Unchanged contents aren't copied again
+	first := rb.LatestSnapshot()
+	clock.Advance(time.Second)
+	assert.Same(t, &first[0], &rb.LatestSnapshot()[0])
+
+	// Reading a snapshot doesn't allocate
+	allocs := testing.AllocsPerRun(100, func() { _ = rb.LatestSnapshot() })
+	assert.Zero(t, allocs)
+
+	// Stopping keeps the last snapshot
+	rb.EnableSnapshotPublishing(0)
+	require.NoError(t, rb.Write(4))
+	clock.Advance(time.Second)
+	assert.Equal(t, []int{2, 3}, rb.LatestSnapshot())
+
+	// So does closing
+	rb.EnableSnapshotPublishing(time.Second)
+	assert.Equal(t, []int{2, 3, 4}, rb.LatestSnapshot())
+	require.NoError(t, rb.Close())
+	clock.Advance(time.Second)
+	assert.Equal(t, []int{2, 3, 4}, rb.LatestSnapshot())
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSPSCBasic(t *testing.T) {
+	assert.Nil(t, ringbuffer.NewSPSC[int](0))
+
+	s := ringbuffer.NewSPSC[int](3)
+	require.NotNil(t, s)
+	assert.Equal(t, 4, s.Capacity())
+
+	_, err := s.TryRead()
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+
+	for i := range 4 {
+		require.NoError(t, s.TryWrite(i))
+	}
+	assert.ErrorIs(t, s.TryWrite(4), errors.ErrIsFull)
+	assert.Equal(t, 4, s.Length())
+
+	for i := range 4 {
+		item, err := s.TryRead()
+		require.NoError(t, err)
+		assert.Equal(t, i, item)
+	}
+	assert.Equal(t, 0, s.Length())
+}
+
+func TestSPSCConcurrentOrder(t *testing.T) {
+	const items = 100000
+
+	s := ringbuffer.NewSPSC[int](64)
+	require.NotNil(t, s)
+
+	go func() {
+		for i := 0; i < items; {
+			if s.TryWrite(i) != nil {
+				runtime.Gosched()
+				continue
+			}
+			i++
+		}
+	}()
+
+	for expected := 0; expected < items; {
+		item, err := s.TryRead()
+		if err != nil {
+			runtime.Gosched()
+			continue
+		}
+		require.Equal(t, expected, item)
+		expected++
+	}
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingBufferStats(t *testing.T) {
+	rb := ringbuffer.New[int](2).WithTimeout(10 * time.Millisecond)
+	require.NotNil(t, rb)
+	assert.Equal(t, ringbuffer.Stats{}, rb.Stats())
+
+	require.NoError(t, rb.Write(1))
+	_, err := rb.WriteMany([]int{2})
+	require.NoError(t, err)
+
+	// Waits for space, then times out
+	assert.ErrorIs(t, rb.Write(3), errors.ErrWriteTimeout)
+
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+	_, err = rb.GetN(1)
+	require.NoError(t, err)
+
+	// Waits for an item, then times out
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, errors.ErrReadTimeout)
+
+	stats := rb.Stats()
+	assert.Equal(t, uint64(2), stats.Writes)
+	assert.Equal(t, uint64(2), stats.Reads)
+	assert.Equal(t, uint64(0), stats.Dropped)
+	assert.GreaterOrEqual(t, stats.BlockedWrites, uint64(1))
+	assert.GreaterOrEqual(t, stats.BlockedReads, uint64(1))
+	assert.Equal(t, uint64(2), stats.Timeouts)
+	assert.Equal(t, 0, stats.Length)
+}
+
+func TestRingBufferResetStats(t *testing.T) {
+	rb := ringbuffer.New[int](2).WithOverwrite(true).WithSeqTracking(true)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), rb.Stats().Dropped)
+
+	rb.ResetStats()
+	assert.Equal(t, ringbuffer.Stats{Length: 2}, rb.Stats())
+
+	require.NoError(t, rb.Write(4))
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+
+	stats := rb.Stats()
+	assert.Equal(t, uint64(1), stats.Writes)
+	assert.Equal(t, uint64(1), stats.Reads)
+	assert.Equal(t, uint64(1), stats.Dropped)
+	assert.Equal(t, 1, stats.Length)
+
+	// Sequence numbers keep counting from the start
+	_, seq, _, err := rb.GetOneSeq()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), seq)
+}
@@ -0,0 +1,60 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeeIntoOverwriteHistory(t *testing.T) {
+	history := ringbuffer.New[int](3).WithOverwrite(true)
+	rb := ringbuffer.New[int](10).WithTee(history)
+	require.NotNil(t, rb)
+
+	require.NoError(t, rb.Write(1))
+	_, err := rb.WriteMany([]int{2, 3})
+	require.NoError(t, err)
+	_, _, err = rb.WriteManyAt([]int{4})
+	require.NoError(t, err)
+	_, err = rb.WriteManyMulti([]int{5}, []int{6})
+	require.NoError(t, err)
+
+	items, err := rb.GetN(6)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, items)
+
+	recent, err := history.GetN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 5, 6}, recent)
+}
+
+func TestTeeFailuresAreSwallowed(t *testing.T) {
+	secondary := ringbuffer.New[int](2).WithBlocking(true)
+	rb := ringbuffer.New[int](10).WithTee(secondary)
+	require.NotNil(t, rb)
+
+	var teeErrs []error
+	rb.WithOnTeeError(func(err error) { teeErrs = append(teeErrs, err) })
+
+	// Neither blocks on the full secondary nor fails the primary write
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+	require.NoError(t, rb.Write(4))
+	require.NoError(t, rb.Write(5))
+	require.NoError(t, rb.Write(6))
+
+	assert.Equal(t, 6, rb.Length(false))
+	assert.Equal(t, 2, secondary.Length(false))
+	require.Len(t, teeErrs, 2)
+	assert.ErrorIs(t, teeErrs[0], errors.ErrIsFull)
+
+	// Failed primary writes are not teed
+	audit := ringbuffer.New[int](5)
+	small := ringbuffer.New[int](1).WithTee(audit)
+	require.NoError(t, small.Write(1))
+	assert.ErrorIs(t, small.Write(2), errors.ErrIsFull)
+	assert.Equal(t, 1, audit.Length(false))
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransfer(t *testing.T) {
+	src := ringbuffer.New[int](5)
+	dst := ringbuffer.New[int](4)
+	require.NotNil(t, src)
+	require.NotNil(t, dst)
+
+	// Wrap the src read head: queued items are 1 2 3 4 across the buffer end
+	_, err := src.WriteMany([]int{0, 0, 0, 1, 2})
+	require.NoError(t, err)
+	_, err = src.GetN(3)
+	require.NoError(t, err)
+	_, err = src.WriteMany([]int{3, 4})
+	require.NoError(t, err)
+
+	// Put the dst write head at the end, so the move wraps there too
+	_, err = dst.WriteMany([]int{0, 0, 0, 10})
+	require.NoError(t, err)
+	_, err = dst.GetN(3)
+	require.NoError(t, err)
+
+	// Limited by the free space of dst
+	moved, err := ringbuffer.Transfer(dst, src, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, moved)
+	assert.True(t, dst.IsFull())
+	assert.Equal(t, 1, src.Length(false))
+
+	items, err := dst.GetN(4)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{10, 1, 2, 3}, items)
+
+	// Limited by n, then by the src length
+	moved, err = ringbuffer.Transfer(dst, src, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, moved)
+
+	moved, err = ringbuffer.Transfer(dst, src, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, moved)
+
+	item, err := dst.GetOne()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, item)
+}
+
+func TestTransferErrors(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+
+	_, err := ringbuffer.Transfer(rb, nil, 1)
+	assert.ErrorIs(t, err, errors.ErrNilBuffer)
+
+	_, err = ringbuffer.Transfer(rb, rb, 1)
+	assert.ErrorIs(t, err, errors.ErrSameBuffer)
+
+	other := ringbuffer.New[int](4)
+	_, err = ringbuffer.Transfer(rb, other, 0)
+	assert.ErrorIs(t, err, errors.ErrInvalidLength)
+
+	require.NoError(t, other.Write(1))
+	require.NoError(t, rb.Close())
+	_, err = ringbuffer.Transfer(rb, other, 1)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 1, other.Length(false))
+}
+
+func TestTransferOppositeDirections(t *testing.T) {
+	a := ringbuffer.New[int](8)
+	b := ringbuffer.New[int](8)
+	require.NotNil(t, a)
+	require.NotNil(t, b)
+
+	_, err := a.WriteMany([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+	_, err = b.WriteMany([]int{5, 6, 7, 8})
+	require.NoError(t, err)
+
+	// Would deadlock if the lock order depended on the argument order
+	var wg sync.WaitGroup
+	for i := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				if i == 0 {
+					ringbuffer.Transfer(a, b, 2)
+				} else {
+					ringbuffer.Transfer(b, a, 2)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 8, a.Length(false)+b.Length(false))
+}
@@ -0,0 +1,148 @@
+package test
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnbounded(t *testing.T) {
+	rb := ringbuffer.NewUnbounded[int]()
+	require.NotNil(t, rb)
+	initial := rb.Capacity()
+
+	// Far more than the initial capacity, with the queue wrapping around on the way
+	const total = 10_000
+	for i := range 5 {
+		require.NoError(t, rb.Write(i))
+	}
+	_, err := rb.GetN(5)
+	require.NoError(t, err)
+
+	for i := 0; i < total; i += 100 {
+		batch := make([]int, 100)
+		for j := range batch {
+			batch[j] = i + j
+		}
+
+		if i%200 == 0 {
+			_, err := rb.WriteMany(batch)
+			require.NoError(t, err)
+			continue
+		}
+		for _, item := range batch {
+			require.NoError(t, rb.Write(item))
+		}
+	}
+
+	assert.Equal(t, total, rb.Length(false))
+	assert.GreaterOrEqual(t, rb.Capacity(), total)
+	assert.Less(t, rb.Capacity(), 2*total, "capacity grows by doubling")
+
+	// FIFO order is kept across every growth, and reads shrink the buffer back
+	for i := range total {
+		item, err := rb.GetOne()
+		require.NoError(t, err)
+		require.Equal(t, i, item)
+		assert.LessOrEqual(t, rb.Length(false), rb.Capacity())
+	}
+	assert.Equal(t, initial, rb.Capacity())
+
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+}
+
+func TestUnboundedBulkOperations(t *testing.T) {
+	rb := ringbuffer.NewUnbounded[int]()
+	require.NotNil(t, rb)
+
+	items := make([]int, 1000)
+	for i := range items {
+		items[i] = i
+	}
+
+	n, err := rb.WriteManyMulti(items[:500], items[500:])
+	require.NoError(t, err)
+	assert.Equal(t, 1000, n)
+
+	// Views taken before a shrink stay readable
+	part1, part2, err := rb.GetNView(900)
+	require.NoError(t, err)
+	assert.Equal(t, items[:900], slices.Concat(part1, part2))
+
+	got, err := rb.GetN(100)
+	require.NoError(t, err)
+	assert.Equal(t, items[900:], got)
+	assert.Equal(t, items[:900], slices.Concat(part1, part2))
+
+	// Transfer into an unbounded buffer moves everything
+	src := ringbuffer.New[int](1000)
+	_, err = src.WriteMany(items)
+	require.NoError(t, err)
+	moved, err := ringbuffer.Transfer(rb, src, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, moved)
+	assert.Equal(t, 1000, rb.Length(false))
+}
+
+func TestUnboundedBlockingReader(t *testing.T) {
+	rb := ringbuffer.NewUnbounded[int]().WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+
+	got := make(chan []int, 1)
+	go func() {
+		items, err := rb.GetN(100)
+		assert.NoError(t, err)
+		got <- items
+	}()
+	require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 1 }, time.Second, time.Millisecond)
+
+	for i := range 100 {
+		require.NoError(t, rb.Write(i))
+	}
+
+	select {
+	case items := <-got:
+		assert.Len(t, items, 100)
+		assert.Equal(t, 99, items[99])
+	case <-time.After(time.Second):
+		t.Fatal("GetN larger than the current capacity should be satisfied as the buffer grows")
+	}
+}
+
+func TestUnboundedGrowthKeepsViewsWithSecureWipe(t *testing.T) {
+	rb := ringbuffer.NewUnbounded[string]().WithSecureWipe(true)
+	require.NotNil(t, rb)
+	initial := rb.Capacity()
+
+	for i := range initial {
+		require.NoError(t, rb.Write(strconv.Itoa(i)))
+	}
+	peeked, _, err := rb.PeekNView(2)
+	require.NoError(t, err)
+	lease, err := rb.LeaseN(2)
+	require.NoError(t, err)
+	defer lease.Release()
+	read, _, err := rb.GetNView(2)
+	require.NoError(t, err)
+
+	// A batch larger than the free space grows the buffer onto a new array first
+	batch := make([]string, initial)
+	for i := range batch {
+		batch[i] = strconv.Itoa(initial + i)
+	}
+	_, err = rb.WriteMany(batch)
+	require.NoError(t, err)
+	require.Greater(t, rb.Capacity(), initial)
+
+	assert.Equal(t, []string{"0", "1"}, peeked)
+	leased, _ := lease.View()
+	assert.Equal(t, []string{"0", "1"}, leased)
+	assert.Equal(t, []string{"2", "3"}, read)
+}
@@ -0,0 +1,38 @@
+package test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerboseLogging(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	exercise := func(rb *ringbuffer.RingBuffer[int]) {
+		_, err := rb.GetOne()
+		require.Error(t, err)
+		require.NoError(t, rb.Close())
+		_, err = rb.GetOne()
+		require.Error(t, err)
+	}
+
+	// Silent by default
+	exercise(ringbuffer.New[int](2).WithBlocking(true).WithTimeout(time.Millisecond))
+	assert.Empty(t, out.String())
+
+	exercise(ringbuffer.New[int](2).WithBlocking(true).WithTimeout(time.Millisecond).WithVerbose(true))
+	logged := out.String()
+	assert.Contains(t, logged, "reader blocking")
+	assert.Contains(t, logged, "reader timed out")
+	assert.Contains(t, logged, "closed with 0 queued items")
+	assert.Contains(t, logged, "GetOne: closed and drained")
+}
@@ -1,7 +1,10 @@
 package test
 
 import (
+	"context"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/AlexsanderHamir/ringbuffer"
 	"github.com/AlexsanderHamir/ringbuffer/errors"
@@ -51,6 +54,10 @@ func TestRingBufferViewModification(t *testing.T) {
 	}
 
 	for i, v := range part1 {
+		// The append reached a slot GetN has since read and zeroed
+		if v == nil {
+			continue
+		}
 		if readValuesMap[v.value] {
 			t.Logf("Value at index %d was modified: got %d, want %d", i, v.value, values[i].value)
 			t.Log("WARNING: Do not modify the buffer view")
@@ -124,6 +131,33 @@ func TestRingBufferGetAllView(t *testing.T) {
 	assert.True(t, rb.IsEmpty())
 }
 
+func TestRingBufferGetAllViewBlocking(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+
+	done := make(chan []int, 1)
+	go func() {
+		part1, part2, err := rb.GetAllView()
+		assert.NoError(t, err)
+		done <- slices.Concat(part1, part2)
+	}()
+	require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 1 }, time.Second, time.Millisecond)
+
+	require.NoError(t, rb.Write(1))
+	select {
+	case items := <-done:
+		assert.Equal(t, []int{1}, items)
+	case <-time.After(time.Second):
+		t.Fatal("GetAllView should return once a write arrives")
+	}
+
+	// Times out if nothing is written
+	rb.WithReadTimeout(20 * time.Millisecond)
+	_, _, err := rb.GetAllView()
+	assert.ErrorIs(t, err, errors.ErrReadTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func TestRingBufferGetManyView(t *testing.T) {
 	rb := ringbuffer.New[*TestValue](10)
 	require.NotNil(t, rb)
@@ -202,3 +236,249 @@ func TestRingBufferGetManyView(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 5, len(part1)+len(part2))
 }
+
+func TestRingBufferGenerationValidation(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+
+	gen := rb.Generation()
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+	assert.False(t, rb.ValidateGeneration(gen))
+
+	part1, part2, gen, err := rb.GetNViewGen(2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, append(part1, part2...))
+	assert.True(t, rb.ValidateGeneration(gen))
+
+	// Reading doesn't invalidate the view
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+	assert.True(t, rb.ValidateGeneration(gen))
+
+	// Writing does
+	require.NoError(t, rb.Write(4))
+	assert.False(t, rb.ValidateGeneration(gen))
+
+	gen = rb.Generation()
+	rb.Flush()
+	assert.False(t, rb.ValidateGeneration(gen))
+}
+
+func TestRingBufferGetUpToNView(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+
+	_, _, err := rb.GetUpToNView(0)
+	assert.ErrorIs(t, err, errors.ErrInvalidLength)
+
+	_, _, err = rb.GetUpToNView(2)
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+
+	// Fewer items than requested: returns what is there
+	_, err = rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+	part1, part2, err := rb.GetUpToNView(10)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, part1)
+	assert.Empty(t, part2)
+	assert.Equal(t, 0, rb.Length(false))
+
+	// Across the buffer end
+	_, err = rb.WriteMany([]int{0, 0, 4})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+	_, err = rb.WriteMany([]int{5, 6})
+	require.NoError(t, err)
+
+	part1, part2, err = rb.GetUpToNView(3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 5}, part1)
+	assert.Equal(t, []int{6}, part2)
+
+	// Capped at n
+	_, err = rb.WriteMany([]int{7, 8})
+	require.NoError(t, err)
+	part1, part2, err = rb.GetUpToNView(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{7}, part1)
+	assert.Empty(t, part2)
+	assert.Equal(t, 1, rb.Length(false))
+}
+
+func TestRingBufferGetUpToNViewBlocking(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+
+	done := make(chan []int, 1)
+	go func() {
+		part1, part2, err := rb.GetUpToNView(4)
+		assert.NoError(t, err)
+		done <- slices.Concat(part1, part2)
+	}()
+	require.Eventually(t, func() bool { return rb.GetBlockedReaders() == 1 }, time.Second, time.Millisecond)
+
+	// A partial batch is enough to wake it
+	require.NoError(t, rb.Write(1))
+	select {
+	case items := <-done:
+		assert.Equal(t, []int{1}, items)
+	case <-time.After(time.Second):
+		t.Fatal("GetUpToNView should return once any item is available")
+	}
+}
+
+func TestRingBufferTryGetNView(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+
+	// Doesn't wait on a blocking buffer
+	start := time.Now()
+	_, _, ok := rb.TryGetNView(1)
+	assert.False(t, ok)
+	assert.Less(t, time.Since(start), time.Second)
+
+	_, _, ok = rb.TryGetNView(0)
+	assert.False(t, ok)
+
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+
+	// Not enough items: nothing is removed
+	_, _, ok = rb.TryGetNView(4)
+	assert.False(t, ok)
+	assert.Equal(t, 3, rb.Length(false))
+
+	part1, part2, ok := rb.TryGetNView(2)
+	assert.True(t, ok)
+	assert.Equal(t, []int{1, 2}, part1)
+	assert.Empty(t, part2)
+	assert.Equal(t, 1, rb.Length(false))
+
+	require.NoError(t, rb.Close())
+	_, _, ok = rb.TryGetNView(1)
+	assert.False(t, ok)
+}
+
+func TestRingBufferLeaseBlocksWrites(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true).WithTimeout(5 * time.Second)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+
+	lease, err := rb.LeaseN(2)
+	require.NoError(t, err)
+	part1, part2 := lease.View()
+	assert.Equal(t, []int{1, 2}, part1)
+	assert.Empty(t, part2)
+	assert.Equal(t, 2, rb.Length(false))
+	assert.Equal(t, 0, rb.Free())
+
+	written := make(chan error, 1)
+	go func() {
+		written <- rb.Write(5)
+	}()
+
+	select {
+	case err := <-written:
+		t.Fatalf("write should block on the leased slots, returned %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The leased items are untouched while the writer waits
+	part1, _ = lease.View()
+	assert.Equal(t, []int{1, 2}, part1)
+
+	lease.Release()
+	lease.Release()
+
+	select {
+	case err := <-written:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("write should proceed once the lease is released")
+	}
+
+	part1, part2 = lease.View()
+	assert.Nil(t, part1)
+	assert.Nil(t, part2)
+
+	items, err := rb.GetN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, items)
+	assert.NoError(t, rb.CheckInvariants())
+}
+
+func TestRingBufferLeaseNonBlocking(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+
+	first, err := rb.LeaseN(1)
+	require.NoError(t, err)
+	second, err := rb.LeaseN(2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.Len())
+	assert.True(t, rb.IsEmpty())
+
+	// Only the slot never read is writable
+	assert.Equal(t, 1, rb.Free())
+	require.NoError(t, rb.Write(4))
+	assert.ErrorIs(t, rb.Write(5), errors.ErrIsFull)
+
+	// Releasing a newer lease frees nothing while an older one is held
+	second.Release()
+	assert.ErrorIs(t, rb.Write(5), errors.ErrIsFull)
+
+	first.Release()
+	_, err = rb.WriteMany([]int{5, 6, 7})
+	require.NoError(t, err)
+	assert.NoError(t, rb.CheckInvariants())
+
+	items, err := rb.GetN(4)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 5, 6, 7}, items)
+}
+
+func TestRingBufferLeaseOverwrite(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithOverwrite(true)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+
+	lease, err := rb.LeaseN(2)
+	require.NoError(t, err)
+
+	// Eviction can't reach the leased slots
+	_, err = rb.WriteMany([]int{5, 6})
+	assert.ErrorIs(t, err, errors.ErrIsFull)
+	assert.ErrorIs(t, rb.Write(5), errors.ErrIsFull)
+	part1, _ := lease.View()
+	assert.Equal(t, []int{1, 2}, part1)
+
+	lease.Release()
+	_, err = rb.WriteMany([]int{5, 6, 7})
+	require.NoError(t, err)
+
+	items, err := rb.GetN(4)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 5, 6, 7}, items)
+	assert.NoError(t, rb.CheckInvariants())
+}
+
+func TestRingBufferLeaseInvalidLength(t *testing.T) {
+	rb := ringbuffer.New[int](4)
+	require.NotNil(t, rb)
+
+	_, err := rb.LeaseN(0)
+	assert.ErrorIs(t, err, errors.ErrInvalidLength)
+	_, err = rb.LeaseN(5)
+	assert.ErrorIs(t, err, errors.ErrInvalidLength)
+	_, err = rb.LeaseN(1)
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+}
@@ -0,0 +1,228 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForLength(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithBlocking(true)
+	require.NotNil(t, rb)
+	defer rb.Close()
+
+	// Can never be satisfied
+	start := time.Now()
+	assert.False(t, rb.WaitForLength(6, 0))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	done := make(chan bool)
+	go func() {
+		done <- rb.WaitForLength(3, time.Second)
+	}()
+
+	for i := range 3 {
+		select {
+		case <-done:
+			t.Fatal("WaitForLength returned before the threshold was reached")
+		case <-time.After(20 * time.Millisecond):
+		}
+		require.NoError(t, rb.Write(i))
+	}
+
+	select {
+	case ok := <-done:
+		assert.True(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("WaitForLength should have returned")
+	}
+
+	// Waiting doesn't consume anything
+	assert.Equal(t, 3, rb.Length(false))
+}
+
+func TestWaitForLengthUnbounded(t *testing.T) {
+	rb := ringbuffer.NewUnbounded[int]().WithBlocking(true)
+	require.NotNil(t, rb)
+	defer rb.Close()
+
+	// More than the current capacity: the buffer grows, so it can still be reached
+	k := rb.Capacity() + 1
+	done := make(chan bool)
+	go func() {
+		done <- rb.WaitForLength(k, time.Second)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	items := make([]int, k)
+	_, err := rb.WriteMany(items)
+	require.NoError(t, err)
+
+	select {
+	case ok := <-done:
+		assert.True(t, ok)
+	case <-time.After(2 * time.Second):
+		t.Fatal("WaitForLength should have returned")
+	}
+	assert.Equal(t, k, rb.Length(false))
+}
+
+func TestWaitForLengthTimeoutAndClose(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	start := time.Now()
+	assert.False(t, rb.WaitForLength(2, 50*time.Millisecond))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	done := make(chan bool)
+	go func() {
+		done <- rb.WaitForLength(2, 0)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	rb.Close()
+
+	select {
+	case ok := <-done:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("WaitForLength should return when the buffer closes")
+	}
+}
+
+func TestWaitForLengthDoesNotStealReaderWakeups(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithBlocking(true)
+	require.NotNil(t, rb)
+	defer rb.Close()
+
+	waiter := make(chan bool)
+	go func() {
+		waiter <- rb.WaitForLength(5, time.Second)
+	}()
+
+	reader := make(chan int)
+	go func() {
+		item, _ := rb.GetOne()
+		reader <- item
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, rb.Write(7))
+
+	select {
+	case item := <-reader:
+		assert.Equal(t, 7, item)
+	case <-time.After(time.Second):
+		t.Fatal("reader should have been woken by the write")
+	}
+
+	assert.False(t, <-waiter)
+}
+
+func TestWaitFor(t *testing.T) {
+	rb := ringbuffer.New[int](8).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	halfFull := func(length, free int, isFull bool) bool { return length >= 4 }
+	done := make(chan bool, 1)
+	go func() {
+		done <- rb.WaitFor(halfFull, 5*time.Second)
+	}()
+
+	for i := range 3 {
+		require.NoError(t, rb.Write(i))
+	}
+	select {
+	case <-done:
+		t.Fatal("WaitFor should wait until the buffer is half full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	require.NoError(t, rb.Write(3))
+	select {
+	case ok := <-done:
+		assert.True(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("WaitFor should return once the predicate holds")
+	}
+	assert.Equal(t, 4, rb.Length(false))
+
+	// Reads wake it up too
+	_, err := rb.WriteMany([]int{4, 5, 6, 7})
+	require.NoError(t, err)
+	go func() {
+		done <- rb.WaitFor(func(length, free int, isFull bool) bool { return free >= 3 && !isFull }, 5*time.Second)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	_, err = rb.GetN(3)
+	require.NoError(t, err)
+	select {
+	case ok := <-done:
+		assert.True(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("WaitFor should re-check after reads")
+	}
+}
+
+func TestWaitForTimeoutAndClose(t *testing.T) {
+	rb := ringbuffer.New[int](4).WithBlocking(true)
+	require.NotNil(t, rb)
+
+	full := func(length, free int, isFull bool) bool { return isFull }
+	assert.False(t, rb.WaitFor(full, 20*time.Millisecond))
+	assert.True(t, rb.WaitFor(func(length, free int, isFull bool) bool { return free == 4 }, 0))
+
+	done := make(chan bool, 1)
+	go func() {
+		done <- rb.WaitFor(full, 0)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, rb.CloseGraceful())
+	select {
+	case ok := <-done:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("WaitFor should return once the buffer is closed")
+	}
+
+	nonBlocking := ringbuffer.New[int](4)
+	assert.False(t, nonBlocking.WaitFor(full, time.Second))
+}
+
+func TestWaitForLengthTimeoutFiresBeforeWait(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithBlocking(true).WithClock(newGapClock())
+	require.NotNil(t, rb)
+
+	// The timeout fires between the deadline check and the wait
+	done := make(chan bool, 1)
+	go func() { done <- rb.WaitForLength(1, time.Minute) }()
+
+	select {
+	case reached := <-done:
+		assert.False(t, reached)
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitForLength missed its timeout")
+	}
+}
+
+func TestWaitForTimeoutFiresBeforeWait(t *testing.T) {
+	rb := ringbuffer.New[int](5).WithBlocking(true).WithClock(newGapClock())
+	require.NotNil(t, rb)
+
+	// The timeout fires between the deadline check and the wait
+	done := make(chan bool, 1)
+	go func() {
+		done <- rb.WaitFor(func(length, free int, isFull bool) bool { return isFull }, time.Minute)
+	}()
+
+	select {
+	case held := <-done:
+		assert.False(t, held)
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitFor missed its timeout")
+	}
+}
@@ -0,0 +1,255 @@
+package test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/AlexsanderHamir/ringbuffer"
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backingSlots returns a slice aliasing every slot of the buffer, taken from a
+// view of the item at slot 0, whose capacity runs to the end of the backing array.
+func backingSlots(t *testing.T, rb *ringbuffer.RingBuffer[string]) []string {
+	t.Helper()
+	part1, _, err := rb.PeekNView(1)
+	require.NoError(t, err)
+	return part1[:cap(part1)]
+}
+
+func TestSecureWipeReset(t *testing.T) {
+	rb := ringbuffer.New[string](4).WithSecureWipe(true)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]string{"key-1", "key-2", "key-3", "key-4"})
+	require.NoError(t, err)
+	slots := backingSlots(t, rb)
+
+	_, err = rb.GetN(4)
+	require.NoError(t, err)
+
+	rb.Reset()
+	for i, slot := range slots {
+		assert.Empty(t, slot, "slot %d still holds a secret", i)
+	}
+}
+
+func TestSecureWipeClearBuffer(t *testing.T) {
+	for _, wipe := range []bool{false, true} {
+		rb := ringbuffer.New[string](4).WithSecureWipe(wipe)
+		require.NotNil(t, rb)
+
+		_, err := rb.WriteMany([]string{"key-1", "key-2", "key-3", "key-4"})
+		require.NoError(t, err)
+		slots := backingSlots(t, rb)
+
+		// Lease two, as reads would zero their slots themselves; the other two stay queued
+		_, err = rb.LeaseN(2)
+		require.NoError(t, err)
+
+		rb.ClearBuffer()
+		if wipe {
+			assert.Equal(t, []string{"", "", "", ""}, slots)
+		} else {
+			// Only the queued region is zeroed
+			assert.Equal(t, []string{"key-1", "key-2", "", ""}, slots)
+		}
+	}
+}
+
+func TestSecureWipePreserveOnClose(t *testing.T) {
+	rb := ringbuffer.New[string](4).WithSecureWipe(true).WithPreserveOnClose(true)
+	require.NotNil(t, rb)
+
+	_, err := rb.WriteMany([]string{"key-1", "key-2", "key-3"})
+	require.NoError(t, err)
+	slots := backingSlots(t, rb)
+
+	_, err = rb.GetOne()
+	require.NoError(t, err)
+
+	require.NoError(t, rb.Close())
+
+	// Consumed slot is wiped, queued items stay readable
+	assert.Equal(t, []string{"", "key-2", "key-3", ""}, slots)
+	items, err := rb.GetN(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key-2", "key-3"}, items)
+}
+
+func TestReadsZeroConsumedSlots(t *testing.T) {
+	errStop := stderrors.New("stop")
+
+	// Each read consumes key-1 and key-2, or more
+	reads := map[string]func(t *testing.T, rb *ringbuffer.RingBuffer[string]){
+		"GetOne": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			for range 2 {
+				_, err := rb.GetOne()
+				require.NoError(t, err)
+			}
+		},
+		"GetOneCtx": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			for range 2 {
+				_, err := rb.GetOneCtx(context.Background())
+				require.NoError(t, err)
+			}
+		},
+		"GetOneSeq": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			for range 2 {
+				_, _, _, err := rb.GetOneSeq()
+				require.NoError(t, err)
+			}
+		},
+		"GetN": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			_, err := rb.GetN(2)
+			require.NoError(t, err)
+		},
+		"Read": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			_, err := rb.Read(make([]string, 2))
+			require.NoError(t, err)
+		},
+		"DrainInto": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			rb.DrainInto(nil)
+		},
+		"GetRange": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			_, err := rb.GetRange(2, 2, 0)
+			require.NoError(t, err)
+		},
+		"ConsumeBatch": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			require.NoError(t, rb.ConsumeBatch(2, func([]string) error { return nil }))
+		},
+		"ConsumeSeq": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			n := 0
+			for range rb.ConsumeSeq() {
+				if n++; n == 2 {
+					break
+				}
+			}
+		},
+		"ForEachDrain": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			_, _, err := rb.ForEachDrain(func(item string) error {
+				if item == "key-3" {
+					return errStop
+				}
+				return nil
+			}, true)
+			require.ErrorIs(t, err, errStop)
+		},
+		"Batches": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			stop := make(chan struct{})
+			batches := rb.Batches(2, stop)
+			assert.Equal(t, []string{"key-1", "key-2"}, <-batches)
+			close(stop)
+			for range batches {
+			}
+		},
+		"Transfer": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			n, err := ringbuffer.Transfer(ringbuffer.New[string](4), rb, 2)
+			require.NoError(t, err)
+			require.Equal(t, 2, n)
+		},
+		"Lease": func(t *testing.T, rb *ringbuffer.RingBuffer[string]) {
+			lease, err := rb.LeaseN(2)
+			require.NoError(t, err)
+			lease.Release()
+		},
+	}
+
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			rb := ringbuffer.New[string](4)
+			require.NotNil(t, rb)
+			_, err := rb.WriteMany([]string{"key-1", "key-2", "key-3", "key-4"})
+			require.NoError(t, err)
+			slots := backingSlots(t, rb)
+
+			read(t, rb)
+			assert.Empty(t, slots[0], "the buffer still references key-1")
+			assert.Empty(t, slots[1], "the buffer still references key-2")
+		})
+	}
+
+	// Views stay valid until the buffer is modified
+	views := map[string]func(rb *ringbuffer.RingBuffer[string]) (part1, part2 []string){
+		"GetNView": func(rb *ringbuffer.RingBuffer[string]) (part1, part2 []string) {
+			part1, part2, err := rb.GetNView(2)
+			require.NoError(t, err)
+			return part1, part2
+		},
+		"TryGetNView": func(rb *ringbuffer.RingBuffer[string]) (part1, part2 []string) {
+			part1, part2, ok := rb.TryGetNView(2)
+			require.True(t, ok)
+			return part1, part2
+		},
+		"GetUpToNView": func(rb *ringbuffer.RingBuffer[string]) (part1, part2 []string) {
+			part1, part2, err := rb.GetUpToNView(2)
+			require.NoError(t, err)
+			return part1, part2
+		},
+	}
+
+	for name, view := range views {
+		t.Run(name, func(t *testing.T) {
+			rb := ringbuffer.New[string](4)
+			require.NotNil(t, rb)
+			_, err := rb.WriteMany([]string{"key-1", "key-2", "key-3", "key-4"})
+			require.NoError(t, err)
+			slots := backingSlots(t, rb)
+
+			part1, part2 := view(rb)
+			assert.Equal(t, []string{"key-1", "key-2"}, append(part1, part2...))
+
+			// The next read or write ends the view, but leaves reused slots alone
+			require.NoError(t, rb.Write("key-5"))
+			assert.Equal(t, []string{"key-5", "", "key-3", "key-4"}, slots)
+		})
+	}
+}
+
+func TestEvictionPolicyZeroesSlots(t *testing.T) {
+	rb := ringbuffer.New[string](4).WithOverwrite(true).
+		WithSizer(func(item string) int { return len(item) }).WithByteBudget(15).
+		WithEvictionPolicy(ringbuffer.EvictionPolicyFunc[string](func(part1, part2 []string) int { return 0 }))
+	require.NotNil(t, rb)
+	_, err := rb.WriteMany([]string{"key-1", "key-2", "key-3"})
+	require.NoError(t, err)
+	slots := backingSlots(t, rb)
+
+	// Room for the longer key takes evicting two items of the byte budget
+	require.NoError(t, rb.WriteBytesBounded("key-4-long"))
+	assert.Equal(t, []string{"", "", "key-3", "key-4-long"}, slots)
+}
+
+func TestGetAllViewZeroesSlotsOnNextRead(t *testing.T) {
+	rb := ringbuffer.New[string](4)
+	require.NotNil(t, rb)
+	_, err := rb.WriteMany([]string{"key-1", "key-2", "key-3"})
+	require.NoError(t, err)
+	slots := backingSlots(t, rb)
+
+	part1, part2, err := rb.GetAllView()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key-1", "key-2", "key-3"}, append(part1, part2...))
+
+	_, err = rb.GetOne()
+	assert.ErrorIs(t, err, errors.ErrIsEmpty)
+	require.NoError(t, rb.Write("key-4"))
+	assert.Equal(t, []string{"", "", "", "key-4"}, slots)
+}
+
+func TestConcatDrainZeroesSlots(t *testing.T) {
+	rb := ringbuffer.New[[]byte](2)
+	require.NotNil(t, rb)
+	_, err := rb.WriteMany([][]byte{[]byte("key-1"), []byte("key-2")})
+	require.NoError(t, err)
+
+	part1, _, err := rb.PeekNView(1)
+	require.NoError(t, err)
+	slots := part1[:cap(part1)]
+
+	assert.Equal(t, []byte("key-1key-2"), ringbuffer.ConcatDrain(rb))
+	assert.Equal(t, [][]byte{nil, nil}, slots)
+}
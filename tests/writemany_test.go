@@ -39,25 +39,36 @@ func TestRingBufferWriteManyOverflow(t *testing.T) {
 		{value: 5},
 	}
 
+	// Can never fit
 	n, err := rb.WriteMany(items)
+	assert.ErrorIs(t, err, errors.ErrTooMuchDataToWrite)
+	assert.Equal(t, 0, n)
+
+	// Fits once items are read
+	n, err = rb.WriteMany(items[:2])
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	n, err = rb.WriteMany(items[2:])
 	assert.ErrorIs(t, err, errors.ErrIsFull)
 	assert.Equal(t, 0, n)
 }
 
 func TestRingBufferWriteManyTimeout(t *testing.T) {
-	rb := ringbuffer.New[*TestValue](2).WithTimeout(100 * time.Millisecond)
+	rb := ringbuffer.New[*TestValue](4).WithTimeout(100 * time.Millisecond)
 	require.NotNil(t, rb)
 	defer rb.Close()
 
+	_, err := rb.WriteMany([]*TestValue{{value: 1}, {value: 2}})
+	require.NoError(t, err)
+
 	items := []*TestValue{
-		{value: 1},
-		{value: 2},
 		{value: 3},
 		{value: 4},
 		{value: 5},
 	}
 	n, err := rb.WriteMany(items)
 	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, errors.ErrWriteTimeout)
 	assert.Equal(t, 0, n)
 }
 
@@ -105,3 +116,281 @@ func TestRingBufferWriteManyBlocking(t *testing.T) {
 		t.Fatal("WriteMany should have completed after space was made")
 	}
 }
+
+func TestRingBufferWriteManyMulti(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	// Move the write position so the fragments wrap around the buffer end
+	_, err := rb.WriteMany([]int{0, 0, 0})
+	require.NoError(t, err)
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+
+	n, err := rb.WriteManyMulti([]int{1, 2}, nil, []int{3}, []int{4})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.True(t, rb.IsFull())
+
+	items, err := rb.GetN(5)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, items)
+
+	// All or nothing
+	_, err = rb.WriteMany([]int{1, 2, 3})
+	require.NoError(t, err)
+	n, err = rb.WriteManyMulti([]int{4, 5}, []int{6})
+	assert.ErrorIs(t, err, errors.ErrIsFull)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, 3, rb.Length(false))
+
+	n, err = rb.WriteManyMulti([]int{1, 2, 3}, []int{4, 5, 6})
+	assert.ErrorIs(t, err, errors.ErrTooMuchDataToWrite)
+	assert.Equal(t, 0, n)
+}
+
+func TestRingBufferWriteManyAt(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	start, wrapAt, err := rb.WriteManyAt([]int{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, start)
+	assert.Equal(t, -1, wrapAt)
+
+	_, err = rb.GetN(2)
+	require.NoError(t, err)
+
+	start, wrapAt, err = rb.WriteManyAt([]int{4, 5, 6, 7})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, start)
+	assert.Equal(t, 2, wrapAt)
+
+	items, err := rb.GetN(5)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5, 6, 7}, items)
+
+	start, wrapAt, err = rb.WriteManyAt(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, start)
+	assert.Equal(t, -1, wrapAt)
+
+	_, _, err = rb.WriteManyAt([]int{1, 2, 3, 4, 5, 6})
+	assert.ErrorIs(t, err, errors.ErrTooMuchDataToWrite)
+}
+
+func TestRingBufferWriteManyRewindsEmptyBuffer(t *testing.T) {
+	rb := ringbuffer.New[int](5)
+	require.NotNil(t, rb)
+
+	// Leave the empty buffer with both positions in the middle
+	_, err := rb.WriteMany([]int{0, 0, 0})
+	require.NoError(t, err)
+	_, err = rb.GetN(3)
+	require.NoError(t, err)
+
+	start, wrapAt, err := rb.WriteManyAt([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+	assert.Equal(t, 0, start)
+	assert.Equal(t, -1, wrapAt)
+
+	part1, part2, err := rb.GetNView(4)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, part1)
+	assert.Empty(t, part2)
+}
+
+func TestRingBufferWriteManyProgress(t *testing.T) {
+	t.Run("Blocking", func(t *testing.T) {
+		rb := ringbuffer.New[int](4).WithBlocking(true).WithTimeout(5 * time.Second)
+		require.NotNil(t, rb)
+
+		items := make([]int, 10)
+		for i := range items {
+			items[i] = i
+		}
+
+		got := make(chan []int, 1)
+		go func() {
+			var all []int
+			for len(all) < len(items) {
+				item, err := rb.GetOne()
+				if !assert.NoError(t, err) {
+					break
+				}
+				all = append(all, item)
+			}
+			got <- all
+		}()
+
+		var progress []int
+		n, err := rb.WriteManyProgress(items, func(written int) {
+			progress = append(progress, written)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, len(items), n)
+		assert.Equal(t, items, <-got)
+
+		// Progress is cumulative, and no chunk is larger than the buffer
+		require.NotEmpty(t, progress)
+		assert.Equal(t, len(items), progress[len(progress)-1])
+		prev := 0
+		for _, written := range progress {
+			assert.Greater(t, written, prev)
+			assert.LessOrEqual(t, written-prev, 4)
+			prev = written
+		}
+	})
+
+	t.Run("Max batch", func(t *testing.T) {
+		rb := ringbuffer.New[int](8).WithMaxBatch(3)
+		require.NotNil(t, rb)
+
+		var progress []int
+		n, err := rb.WriteManyProgress([]int{1, 2, 3, 4, 5, 6, 7}, func(written int) {
+			progress = append(progress, written)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 7, n)
+		assert.Equal(t, []int{3, 6, 7}, progress)
+	})
+
+	t.Run("Non-blocking partial write", func(t *testing.T) {
+		rb := ringbuffer.New[int](3)
+		require.NotNil(t, rb)
+		require.NoError(t, rb.Write(0))
+
+		n, err := rb.WriteManyProgress([]int{1, 2, 3, 4}, nil)
+		assert.ErrorIs(t, err, errors.ErrIsFull)
+		assert.Equal(t, 2, n)
+
+		items, err := rb.GetN(3)
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 2}, items)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		rb := ringbuffer.New[int](2).WithBlocking(true).WithTimeout(10 * time.Millisecond)
+		require.NotNil(t, rb)
+
+		n, err := rb.WriteManyProgress([]int{1, 2, 3}, nil)
+		assert.ErrorIs(t, err, errors.ErrWriteTimeout)
+		assert.Equal(t, 2, n)
+	})
+}
+
+func TestRingBufferWriteManyCapacityBoundaries(t *testing.T) {
+	const size = 5
+
+	seq := func(n int) []int {
+		items := make([]int, n)
+		for i := range items {
+			items[i] = i + 1
+		}
+		return items
+	}
+
+	// Empty buffers with both positions at 0 and in the middle
+	setups := map[string]func(rb *ringbuffer.RingBuffer[int]){
+		"Fresh": func(rb *ringbuffer.RingBuffer[int]) {},
+		"Rotated": func(rb *ringbuffer.RingBuffer[int]) {
+			_, err := rb.WriteMany([]int{0, 0, 0})
+			require.NoError(t, err)
+			_, err = rb.GetN(3)
+			require.NoError(t, err)
+		},
+	}
+
+	for name, setup := range setups {
+		t.Run(name, func(t *testing.T) {
+			t.Run("Exactly capacity", func(t *testing.T) {
+				rb := ringbuffer.New[int](size)
+				require.NotNil(t, rb)
+				setup(rb)
+
+				n, err := rb.WriteMany(seq(size))
+				require.NoError(t, err)
+				assert.Equal(t, size, n)
+
+				assert.Equal(t, size, rb.Length(false))
+				assert.Equal(t, 0, rb.Free())
+				assert.True(t, rb.IsFull())
+				assert.False(t, rb.IsEmpty())
+				assert.ErrorIs(t, rb.Write(0), errors.ErrIsFull)
+
+				items, err := rb.GetN(size)
+				require.NoError(t, err)
+				assert.Equal(t, seq(size), items)
+				assert.True(t, rb.IsEmpty())
+				assert.False(t, rb.IsFull())
+			})
+
+			t.Run("Capacity minus one", func(t *testing.T) {
+				rb := ringbuffer.New[int](size)
+				require.NotNil(t, rb)
+				setup(rb)
+
+				n, err := rb.WriteMany(seq(size - 1))
+				require.NoError(t, err)
+				assert.Equal(t, size-1, n)
+
+				assert.Equal(t, size-1, rb.Length(false))
+				assert.Equal(t, 1, rb.Free())
+				assert.False(t, rb.IsFull())
+
+				require.NoError(t, rb.Write(size))
+				assert.True(t, rb.IsFull())
+
+				items, err := rb.GetN(size)
+				require.NoError(t, err)
+				assert.Equal(t, seq(size), items)
+			})
+
+			t.Run("Capacity plus one", func(t *testing.T) {
+				rb := ringbuffer.New[int](size)
+				require.NotNil(t, rb)
+				setup(rb)
+
+				n, err := rb.WriteMany(seq(size + 1))
+				assert.ErrorIs(t, err, errors.ErrTooMuchDataToWrite)
+				assert.Equal(t, 0, n)
+				assert.True(t, rb.IsEmpty())
+				assert.Equal(t, size, rb.Free())
+			})
+
+			t.Run("Capacity plus one blocking", func(t *testing.T) {
+				rb := ringbuffer.New[int](size).WithTimeout(20 * time.Millisecond)
+				require.NotNil(t, rb)
+				setup(rb)
+
+				// Fails right away instead of waiting for room that can't exist
+				n, err := rb.WriteMany(seq(size + 1))
+				assert.ErrorIs(t, err, errors.ErrTooMuchDataToWrite)
+				assert.Equal(t, 0, n)
+				assert.True(t, rb.IsEmpty())
+			})
+		})
+	}
+
+	t.Run("Filling the rest across the end", func(t *testing.T) {
+		rb := ringbuffer.New[int](size)
+		require.NotNil(t, rb)
+
+		// Two items left in the middle, so the write wraps and ends on the read position
+		_, err := rb.WriteMany([]int{0, 0, 1, 2})
+		require.NoError(t, err)
+		_, err = rb.GetN(2)
+		require.NoError(t, err)
+
+		n, err := rb.WriteMany([]int{3, 4, 5})
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+		assert.True(t, rb.IsFull())
+		assert.Equal(t, size, rb.Length(false))
+		assert.Equal(t, 0, rb.Free())
+
+		items, err := rb.GetN(size)
+		require.NoError(t, err)
+		assert.Equal(t, seq(size), items)
+	})
+}
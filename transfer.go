@@ -0,0 +1,82 @@
+package ringbuffer
+
+import (
+	"unsafe"
+
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+)
+
+// Transfer moves up to n items from the read head of src to the write head of dst,
+// preserving FIFO order, without an intermediate slice.
+// Behavior:
+// - Moves min(n, src length, dst free space) items and returns how many were moved
+// - Never blocks, moving 0 items is not an error
+// - Both buffers are locked for the whole move, always in the same order, so
+// concurrent transfers in opposite directions can't deadlock
+// - Wakes waiting readers of dst and waiting writers of src
+// - Write options of dst (overwrite, tee, dedup) and the cloner of src are not applied
+// - Returns ErrInvalidLength if n <= 0
+// - Returns ErrSameBuffer if dst and src are the same buffer
+// - Returns io.EOF if src is closed and drained, or dst is closed
+func Transfer[T any](dst, src *RingBuffer[T], n int) (int, error) {
+	if dst == nil || src == nil {
+		return 0, errors.ErrNilBuffer
+	}
+
+	if n <= 0 {
+		return 0, errors.ErrInvalidLength
+	}
+
+	if dst == src {
+		return 0, errors.ErrSameBuffer
+	}
+
+	defer dst.unlockWriters(dst.lockWriters())
+
+	first, second := dst, src
+	if uintptr(unsafe.Pointer(src)) < uintptr(unsafe.Pointer(dst)) {
+		first, second = src, dst
+	}
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	if err := src.readErr(true, "Transfer"); err != nil {
+		return 0, err
+	}
+
+	if err := dst.writeErr(); err != nil {
+		return 0, err
+	}
+
+	dst.growFor(min(n, src.Length(true)))
+	n = min(n, src.Length(true), dst.writeSpace(false))
+	if n == 0 {
+		return 0, nil
+	}
+
+	// Each part is contiguous in src, copyIn takes care of the dst wrap
+	dst.rewindIfEmpty()
+	part1, part2 := src.view(n)
+	for _, part := range [][]T{part1, part2} {
+		if dst.less != nil {
+			dst.insertSorted(part)
+			continue
+		}
+
+		dst.copyIn(part)
+		dst.w = (dst.w + len(part)) % dst.size
+	}
+	dst.isFull = dst.w == dst.r
+	dst.markModified()
+	dst.signalReaders(n)
+
+	src.consume(n)
+	src.afterRead(n)
+	if src.block && src.blockedWriters > 0 {
+		src.readCond.Signal()
+	}
+
+	return n, nil
+}
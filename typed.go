@@ -0,0 +1,15 @@
+package ringbuffer
+
+// NewBytes returns a new RingBuffer of bytes with the given size, see New.
+// Bulk operations on it (WriteMany, GetN, Read...) move bytes with copy,
+// and NewByteRing builds on it to serve io.Reader and io.Writer.
+// Returns nil if size <= 0.
+func NewBytes(size int) *RingBuffer[byte] {
+	return New[byte](size)
+}
+
+// NewInts returns a new RingBuffer of ints with the given size, see New.
+// Returns nil if size <= 0.
+func NewInts(size int) *RingBuffer[int] {
+	return New[int](size)
+}
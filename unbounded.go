@@ -0,0 +1,64 @@
+package ringbuffer
+
+// unboundedMinSize is the initial, and smallest, capacity of an unbounded buffer.
+const unboundedMinSize = 16
+
+// NewUnbounded returns a new RingBuffer with no fixed capacity, for producers that
+// can neither drop data nor block.
+// Behavior:
+// - Writes never block nor return ErrIsFull: when the buffer is full its capacity
+// doubles, like append, keeping the queued items in FIFO order
+// - Reads halve the capacity while at most a quarter of it is used, down to the
+// initial capacity, to release memory
+// - Reads block on an empty buffer only if blocking is enabled, as usual
+// - Capacity reports the current capacity, which changes over time
+// - Resizing moves the items to a new array: views taken before keep pointing at
+// the old one, which is never written again, nor wiped with WithSecureWipe, and stay readable
+func NewUnbounded[T any]() *RingBuffer[T] {
+	r := New[T](unboundedMinSize)
+	r.unbounded = true
+	r.minSize = unboundedMinSize
+
+	return r
+}
+
+// growFor doubles the capacity of an unbounded buffer until n more items fit.
+// The old array is left untouched, even with WithSecureWipe, so views on it stay readable:
+// reads already zeroed the slots they consumed, the others hold queued items or are in
+// use by views and leases.
+// Must be called when locked.
+func (r *RingBuffer[T]) growFor(n int) {
+	if !r.unbounded {
+		return
+	}
+
+	need := r.Length(true) + n
+	if need <= r.size {
+		return
+	}
+
+	size := r.size
+	for size < need {
+		size *= 2
+	}
+	r.resize(size, false)
+}
+
+// shrinkIfSparse halves the capacity of an unbounded buffer, as many times as needed,
+// while at most a quarter of it is used. The old array is left untouched so views on it stay readable.
+// Must be called when locked.
+func (r *RingBuffer[T]) shrinkIfSparse() {
+	if !r.unbounded {
+		return
+	}
+
+	n := r.Length(true)
+	size := r.size
+	for size > r.minSize && n <= size/4 {
+		size = max(size/2, r.minSize)
+	}
+
+	if size != r.size {
+		r.resize(size, false)
+	}
+}
@@ -2,8 +2,9 @@ package ringbuffer
 
 import (
 	"context"
-	"fmt"
 	"io"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/AlexsanderHamir/ringbuffer/errors"
@@ -22,7 +23,7 @@ func (r *RingBuffer[T]) setErr(err error, locked bool) error {
 
 	switch err {
 	// Internal errors are temporary
-	case nil, errors.ErrIsEmpty, errors.ErrIsFull, errors.ErrAcquireLock, errors.ErrTooMuchDataToWrite, errors.ErrIsNotEmpty, context.DeadlineExceeded:
+	case nil, errors.ErrIsEmpty, errors.ErrIsFull, errors.ErrAcquireLock, errors.ErrTooMuchDataToWrite, errors.ErrIsNotEmpty, context.DeadlineExceeded, errors.ErrReadTimeout, errors.ErrWriteTimeout:
 		return err
 	default:
 		r.err = err
@@ -30,83 +31,436 @@ func (r *RingBuffer[T]) setErr(err error, locked bool) error {
 			r.readCond.Broadcast()
 			r.writeCond.Broadcast()
 		}
+		if r.stateWaiters > 0 {
+			r.stateCond.Broadcast()
+		}
 	}
 	return err
 }
 
 // readErr checks for errors in the ring buffer
-func (r *RingBuffer[T]) readErr(locked bool, log bool, location string) error {
+func (r *RingBuffer[T]) readErr(locked bool, location string) error {
 	if !locked {
 		r.mu.Lock()
 		defer r.mu.Unlock()
 	}
 
+	if !r.initialized() {
+		return errors.ErrNotInitialized
+	}
+
 	if r.err != nil {
 		if r.err == io.EOF {
 			if r.w == r.r && !r.isFull {
-				if log {
-					fmt.Println("readErr EOF: ", location)
-				}
+				r.logVerbose("%s: closed and drained", location)
 				return io.EOF
 			}
 			return nil
 		}
+		r.logVerbose("%s: %v", location, r.err)
 		return r.err
 	}
 	return nil
 }
 
-// waitRead waits for a read event
-// Returns true if a read may have happened.
-// Returns false if waited longer than rTimeout.
+// peekLock locks for a method that doesn't modify the buffer: with the shared read
+// lock if WithRWMutex is enabled, so such methods run concurrently, with the exclusive
+// lock otherwise. Returns whether the read lock was taken, to pass to peekUnlock.
+func (r *RingBuffer[T]) peekLock() (shared bool) {
+	return r.mu.rLock()
+}
+
+// peekUnlock releases the lock taken by peekLock.
+func (r *RingBuffer[T]) peekUnlock(shared bool) {
+	r.mu.rUnlock(shared)
+}
+
+// wakeOnDone broadcasts cond once ctx is done, so a goroutine waiting on cond for
+// ctx notices it; the others go back to waiting. Returns the function that stops
+// watching ctx, which may be called when locked.
+func (r *RingBuffer[T]) wakeOnDone(ctx context.Context, cond *sync.Cond) (stop func() bool) {
+	return context.AfterFunc(ctx, func() {
+		r.mu.Lock()
+		cond.Broadcast()
+		r.mu.Unlock()
+	})
+}
+
+// lockWriters takes the producer lock if WithContiguousBatches is enabled, see it.
+// Returns whether the lock was taken, to pass to unlockWriters.
+// Must be called without holding the lock.
+func (r *RingBuffer[T]) lockWriters() (locked bool) {
+	if !r.contiguousBatches.Load() {
+		return false
+	}
+
+	r.writers.Lock()
+	return true
+}
+
+// unlockWriters releases the producer lock taken by lockWriters.
+func (r *RingBuffer[T]) unlockWriters(locked bool) {
+	if locked {
+		r.writers.Unlock()
+	}
+}
+
+// logVerbose logs a diagnostic message when verbose mode is enabled.
+// Must be called when locked.
+func (r *RingBuffer[T]) logVerbose(format string, args ...any) {
+	if r.verbose {
+		log.Printf("ringbuffer: "+format, args...)
+	}
+}
+
+// writeErr checks whether the ring buffer accepts writes.
+// Unlike readErr, a closed buffer rejects writes even while items are still queued.
+// Must be called when locked.
+func (r *RingBuffer[T]) writeErr() error {
+	if !r.initialized() {
+		return errors.ErrNotInitialized
+	}
+	return r.err
+}
+
+// initialized reports whether the buffer was created by a constructor: they all
+// allocate at least one slot, while a zero-value buffer has none.
+func (r *RingBuffer[T]) initialized() bool {
+	return r.size > 0
+}
+
+// markModified records that slots were written or cleared: it bumps the
+// generation and publishes the new length for lock-free readers.
+// Must be called when locked.
+func (r *RingBuffer[T]) markModified() {
+	r.clearStale()
+	r.generation.Add(1)
+	r.publishLength()
+}
+
+// publishLength stores the current length for IsEmptyFast.
+// Must be called when locked.
+func (r *RingBuffer[T]) publishLength() {
+	r.length.Store(int64(r.Length(true)))
+	if r.stateWaiters > 0 {
+		r.stateCond.Broadcast()
+	}
+}
+
+// afterRead counts n read items, publishes the new length and fires the
+// drained hook when a read empties a gracefully closed buffer.
+// Must be called when locked.
+func (r *RingBuffer[T]) afterRead(n int) {
+	r.reads.Add(uint64(n))
+	r.shrinkIfSparse()
+	r.publishLength()
+	r.startPrefetch()
+
+	if !r.draining || r.w != r.r || r.isFull {
+		return
+	}
+
+	r.draining = false
+	if r.onDrained != nil {
+		r.onDrained()
+	}
+}
+
+// waitRead waits for a read event on behalf of a write that may wait several times.
+// The first wait sets *deadline, zero until then, from rTimeout, so the total wait of
+// the write is bounded by it however many wakeups don't let it proceed.
+// location names the waiting operation for diagnostics and the timeout hook.
+// Returns nil if a read may have happened.
+// Returns ErrWriteTimeout, wrapping context.DeadlineExceeded, if the write waited longer than rTimeout.
+// Returns ErrTooManyWaiters if the blocked writers cap is reached.
 // Must be called when locked and returns locked.
-func (r *RingBuffer[T]) waitRead() (ok bool) {
-	r.blockedWriters++
+func (r *RingBuffer[T]) waitRead(deadline *time.Time, location string) error {
+	if deadline.IsZero() {
+		*deadline = r.writeDeadline()
+	}
 
-	defer func() { r.blockedWriters-- }()
+	return r.waitReadUntil(*deadline, location)
+}
 
+// writeDeadline returns the deadline of a write that starts waiting now,
+// or the zero time if writes don't time out.
+// Must be called when locked.
+func (r *RingBuffer[T]) writeDeadline() time.Time {
 	if r.rTimeout <= 0 {
+		return time.Time{}
+	}
+
+	return r.clock.Now().Add(r.rTimeout)
+}
+
+// waitReadPriority waits for a read event on behalf of a normal or priority writer.
+// A slot freed inside the reserved capacity only helps priority writers, so a
+// normal writer that got the wakeup for it passes it on before going back to sleep.
+// Must be called when locked and returns locked.
+func (r *RingBuffer[T]) waitReadPriority(priority bool, deadline *time.Time, location string) error {
+	if !priority {
+		if r.priorityWriters > 0 && r.availableSpace() > 0 {
+			r.readCond.Broadcast()
+		}
+		return r.waitRead(deadline, location)
+	}
+
+	r.priorityWriters++
+	defer func() {
+		r.priorityWriters--
+	}()
+
+	return r.waitRead(deadline, location)
+}
+
+// waitReadUntil waits for a read event or for the deadline to pass.
+// A zero deadline waits without timeout.
+// Must be called when locked and returns locked.
+func (r *RingBuffer[T]) waitReadUntil(deadline time.Time, location string) error {
+	if r.maxBlockedWriters > 0 && r.blockedWriters >= r.maxBlockedWriters {
+		r.logVerbose("writer rejected, %d writers already blocked", r.blockedWriters)
+		return errors.ErrTooManyWaiters
+	}
+
+	r.logVerbose("writer blocking, %d items queued, %d writers already blocked", r.Length(true), r.blockedWriters)
+	r.writeWaits.Add(1)
+	r.blockedWriters++
+	r.waitingWriters.Store(int64(r.blockedWriters))
+
+	defer func() {
+		r.blockedWriters--
+		r.waitingWriters.Store(int64(r.blockedWriters))
+	}()
+
+	var start time.Time
+	if r.latency != nil {
+		start = r.clock.Now()
+	}
+
+	if deadline.IsZero() {
 		r.readCond.Wait()
-		return true
+		r.recordWait(start)
+		return nil
 	}
 
-	start := time.Now()
-	defer time.AfterFunc(r.rTimeout, r.readCond.Broadcast).Stop()
+	remaining := deadline.Sub(r.clock.Now())
+	if remaining <= 0 {
+		r.notifyTimeout(location)
+		return errors.ErrWriteTimeout
+	}
+
+	r.armWaitTimer(&r.readTimer, &r.readCond, deadline)
 
 	r.readCond.Wait()
-	if time.Since(start) >= r.rTimeout {
-		r.setErr(context.DeadlineExceeded, true)
-		return false
+	r.recordWait(start)
+	if !r.clock.Now().Before(deadline) {
+		r.logVerbose("%s: writer timed out, %d writers blocked", location, r.blockedWriters)
+		r.setErr(errors.ErrWriteTimeout, true)
+		r.notifyTimeout(location)
+		return errors.ErrWriteTimeout
 	}
 
-	return true
+	return nil
 }
 
-// waitWrite waits for a write event
-// Returns true if a write may have happened.
-// Returns false if waited longer than wTimeout.
+// waitWrite waits for a write event on behalf of a read that may wait several times.
+// The first wait sets *deadline, zero until then, from wTimeout, so the total wait of
+// the read is bounded by it however many wakeups don't let it proceed.
+// Returns nil if a write may have happened.
+// Returns ErrReadTimeout, wrapping context.DeadlineExceeded, if the read waited longer than wTimeout.
+// Returns ErrTooManyWaiters if the blocked readers cap is reached.
+// location names the waiting operation for diagnostics and the timeout hook.
 // Must be called when locked and returns locked.
-func (r *RingBuffer[T]) waitWrite() (ok bool) {
+func (r *RingBuffer[T]) waitWrite(deadline *time.Time, location string) error {
+	if deadline.IsZero() {
+		*deadline = r.readDeadline()
+	}
+
+	return r.waitWriteUntil(*deadline, location)
+}
+
+// readDeadline returns the deadline of a read that starts waiting now,
+// or the zero time if reads don't time out.
+// Must be called when locked.
+func (r *RingBuffer[T]) readDeadline() time.Time {
+	if r.wTimeout <= 0 {
+		return time.Time{}
+	}
+
+	return r.clock.Now().Add(r.wTimeout)
+}
+
+// waitWriteN waits for a write event on behalf of a reader that needs n items.
+// Readers needing more than one item are counted as bulk readers, see signalReaders.
+// Must be called when locked and returns locked.
+func (r *RingBuffer[T]) waitWriteN(n int, deadline *time.Time, location string) error {
+	if n <= 1 {
+		return r.waitWrite(deadline, location)
+	}
+
+	r.bulkReaders++
+	defer func() {
+		r.bulkReaders--
+	}()
+
+	return r.waitWrite(deadline, location)
+}
+
+// waitWriteUntil waits for a write event or for the deadline to pass.
+// A zero deadline waits without timeout.
+// Must be called when locked and returns locked.
+func (r *RingBuffer[T]) waitWriteUntil(deadline time.Time, location string) error {
+	if r.maxBlockedReaders > 0 && r.blockedReaders >= r.maxBlockedReaders {
+		r.logVerbose("reader rejected, %d readers already blocked", r.blockedReaders)
+		return errors.ErrTooManyWaiters
+	}
+
+	r.logVerbose("reader blocking, %d items queued, %d readers already blocked", r.Length(true), r.blockedReaders)
+	r.readWaits.Add(1)
 	r.blockedReaders++
+	r.waitingReaders.Store(int64(r.blockedReaders))
 
 	defer func() {
 		r.blockedReaders--
+		r.waitingReaders.Store(int64(r.blockedReaders))
 	}()
 
-	if r.wTimeout <= 0 {
+	var start time.Time
+	if r.latency != nil {
+		start = r.clock.Now()
+	}
+
+	if deadline.IsZero() {
 		r.writeCond.Wait()
-		return true
+		r.recordWait(start)
+		return nil
 	}
 
-	start := time.Now()
+	remaining := deadline.Sub(r.clock.Now())
+	if remaining <= 0 {
+		r.notifyTimeout(location)
+		return errors.ErrReadTimeout
+	}
 
-	defer time.AfterFunc(r.wTimeout, r.writeCond.Broadcast).Stop()
+	r.armWaitTimer(&r.writeTimer, &r.writeCond, deadline)
 
 	r.writeCond.Wait()
-	if time.Since(start) >= r.wTimeout {
-		r.setErr(context.DeadlineExceeded, true)
+	r.recordWait(start)
+	if !r.clock.Now().Before(deadline) {
+		r.logVerbose("%s: reader timed out, %d readers blocked", location, r.blockedReaders)
+		r.setErr(errors.ErrReadTimeout, true)
+		r.notifyTimeout(location)
+		return errors.ErrReadTimeout
+	}
+
+	return nil
+}
+
+// waitTimer wakes the goroutines waiting on a condition variable at the earliest of
+// their deadlines, so timed waits share one timer instead of allocating one each.
+// Waiters woken before their own deadline wait again, arming it for the next one.
+// Guarded by the buffer lock.
+type waitTimer struct {
+	timer Timer
+	clock Clock     // Clock that created timer
+	at    time.Time // Deadline the timer is armed for, zero when idle
+	fire  func()
+}
+
+// resettableTimer is implemented by timers that can be re-armed, like *time.Timer.
+// Other timers are replaced by a new one instead.
+type resettableTimer interface {
+	Reset(d time.Duration) bool
+}
+
+// armWaitTimer makes t broadcast *cond no later than deadline.
+// Must be called when locked.
+func (r *RingBuffer[T]) armWaitTimer(t *waitTimer, cond **sync.Cond, deadline time.Time) {
+	if !t.at.IsZero() && !deadline.Before(t.at) {
+		return
+	}
+	t.at = deadline
+
+	if t.fire == nil {
+		t.fire = func() {
+			r.mu.Lock()
+			// A fire racing with a re-arm for a later deadline leaves it armed
+			if !r.clock.Now().Before(t.at) {
+				t.at = time.Time{}
+			}
+			(*cond).Broadcast()
+			r.mu.Unlock()
+		}
+	}
+
+	d := deadline.Sub(r.clock.Now())
+	if rt, ok := t.timer.(resettableTimer); ok && t.clock == r.clock {
+		rt.Reset(d)
+		return
+	}
+
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	t.timer, t.clock = r.clock.AfterFunc(d, t.fire), r.clock
+}
+
+// waitCondUntil waits on *cond, shared by several waiters, until woken or until the
+// deadline passes, using t to wake it: unlike a timer broadcasting without the lock,
+// t can't fire between the deadline check and the wait and leave it asleep.
+// A zero deadline waits without timeout.
+// Returns false without waiting once the deadline has passed.
+// Must be called when locked and returns locked.
+func (r *RingBuffer[T]) waitCondUntil(t *waitTimer, cond **sync.Cond, deadline time.Time) bool {
+	if deadline.IsZero() {
+		(*cond).Wait()
+		return true
+	}
+
+	r.armWaitTimer(t, cond, deadline)
+	if !r.clock.Now().Before(deadline) {
 		return false
 	}
 
+	(*cond).Wait()
 	return true
 }
+
+// notifyTimeout counts a timed out wait of the named operation, see Stats, and reports it to the timeout hook.
+// The hook runs on its own goroutine, so it never delays the operation returning.
+// Must be called when locked.
+func (r *RingBuffer[T]) notifyTimeout(location string) {
+	r.timeouts.Add(1)
+
+	hook := r.onTimeout
+	if hook == nil {
+		return
+	}
+
+	r.goroutines.Add(1)
+	go func() {
+		defer r.goroutines.Done()
+		hook(location)
+	}()
+}
+
+// signalReaders wakes blocked readers after n items were written: one per item,
+// up to the number of blocked readers.
+// Length waiters don't consume the wakeup, and a bulk reader may go back to sleep
+// with it if its count isn't met yet while a reader needing fewer items stays parked,
+// so while any of them are parked everyone is woken.
+// Must be called when locked.
+func (r *RingBuffer[T]) signalReaders(n int) {
+	if !r.block {
+		return
+	}
+
+	if r.lengthWaiters > 0 || r.bulkReaders > 0 || n >= r.blockedReaders {
+		r.writeCond.Broadcast()
+		return
+	}
+
+	for range min(n, r.blockedReaders) {
+		r.writeCond.Signal()
+	}
+}
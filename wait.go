@@ -0,0 +1,262 @@
+package ringbuffer
+
+import (
+	stderrors "errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/AlexsanderHamir/ringbuffer/errors"
+)
+
+// WaitForLength blocks until the buffer holds at least k items.
+// Returns true if the threshold was reached, false if the timeout elapsed
+// or the buffer was closed first.
+// Behavior:
+// - Returns false immediately if k is larger than the capacity of a bounded buffer,
+// since it can never be satisfied; an unbounded buffer grows, so any k can be waited for
+// - A timeout of 0 or less waits without a deadline
+// - In non-blocking mode it doesn't wait and only reports the current state
+// - Doesn't consume any item
+func (r *RingBuffer[T]) WaitForLength(k int, timeout time.Duration) bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.validReadLength(k) {
+		return false
+	}
+
+	if r.Length(true) >= k {
+		return r.err == nil
+	}
+
+	if !r.block {
+		return false
+	}
+
+	r.lengthWaiters++
+	defer func() { r.lengthWaiters-- }()
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = r.clock.Now().Add(timeout)
+	}
+
+	for r.Length(true) < k {
+		if r.err != nil {
+			return false
+		}
+
+		if !r.waitCondUntil(&r.writeTimer, &r.writeCond, deadline) {
+			return false
+		}
+	}
+
+	return r.err == nil
+}
+
+// WaitFor blocks until pred holds for the current length, free space and full flag,
+// generalizing WaitForLength to any condition, e.g. "at least half full".
+// Returns true if pred held, false if the timeout elapsed or the buffer was closed first.
+// Behavior:
+// - pred is checked right away, then again after every read or write
+// - pred runs under the lock, so it must be cheap and must not call back into the buffer
+// - A timeout of 0 or less waits without a deadline
+// - In non-blocking mode it doesn't wait and only reports the current state
+// - Doesn't consume any item
+func (r *RingBuffer[T]) WaitFor(pred func(length, free int, isFull bool) bool, timeout time.Duration) bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	holds := func() bool {
+		return pred(r.Length(true), r.availableSpace(), r.isFull)
+	}
+
+	if r.err != nil || holds() {
+		return r.err == nil
+	}
+
+	if !r.block {
+		return false
+	}
+
+	if r.stateCond == nil {
+		r.stateCond = sync.NewCond(&r.mu)
+	}
+	r.stateWaiters++
+	defer func() { r.stateWaiters-- }()
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = r.clock.Now().Add(timeout)
+	}
+
+	for !holds() {
+		if r.err != nil {
+			return false
+		}
+
+		if !r.waitCondUntil(&r.stateTimer, &r.stateCond, deadline) {
+			return false
+		}
+	}
+
+	return r.err == nil
+}
+
+// PeekOneBlocking returns the next item without removing it from the buffer,
+// waiting for one to be written if the buffer is empty.
+// Behavior:
+// - Blocks until an item is available in blocking mode
+// - A timeout of 0 or less uses the buffer's read timeout
+// - Returns ErrIsEmpty if buffer is empty and not blocking
+// - Returns ErrReadTimeout if timeout occurs
+// - Returns io.EOF if the buffer is closed and empty
+func (r *RingBuffer[T]) PeekOneBlocking(timeout time.Duration) (item T, err error) {
+	if r == nil {
+		return item, errors.ErrNilBuffer
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.readErr(true, "PeekOneBlocking"); err != nil {
+		return item, err
+	}
+
+	if timeout <= 0 {
+		timeout = r.wTimeout
+	}
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = r.clock.Now().Add(timeout)
+	}
+
+	for r.w == r.r && !r.isFull {
+		if !r.block {
+			return item, errors.ErrIsEmpty
+		}
+
+		if err := r.waitWriteUntil(deadline, "PeekOneBlocking"); err != nil {
+			return item, err
+		}
+
+		if err := r.readErr(true, "PeekOneBlocking"); err != nil {
+			return item, err
+		}
+	}
+
+	// Peeking doesn't consume the item, so pass the wakeup on to a real reader.
+	if r.blockedReaders > 0 {
+		r.writeCond.Signal()
+	}
+
+	return r.clone(r.buf[r.r]), nil
+}
+
+// GetRange waits until at least minN items are queued, then removes and returns up to maxN
+// of them, for batches worth processing but capped in size.
+// Behavior:
+// - Requires 0 < minN <= maxN <= size, except for unbounded buffers, see NewUnbounded
+// - A timeout of 0 or less uses the buffer's read timeout
+// - On timeout, returns the fewer than minN items available along with ErrReadTimeout,
+// or a nil error with WithQuietRangeTimeout; ErrReadTimeout alone if none are available
+// - Once the buffer is closed, returns the remaining items even if fewer than minN,
+// then io.EOF when drained
+// Returns:
+// - ErrInvalidLength if the bounds are invalid
+// - ErrIsEmpty if fewer than minN items are queued and not blocking
+func (r *RingBuffer[T]) GetRange(minN, maxN int, timeout time.Duration) (items []T, err error) {
+	if r == nil {
+		return nil, errors.ErrNilBuffer
+	}
+
+	if minN <= 0 || minN > maxN {
+		return nil, errors.ErrInvalidLength
+	}
+
+	r.mu.Lock()
+	defer func() {
+		if len(items) > 0 && r.block && r.blockedWriters > 0 {
+			r.readCond.Signal()
+		}
+		r.mu.Unlock()
+	}()
+
+	if !r.initialized() {
+		return nil, errors.ErrNotInitialized
+	}
+
+	// otherwise it will block forever
+	if maxN > r.size && !r.unbounded {
+		return nil, errors.ErrInvalidLength
+	}
+
+	if err := r.readErr(true, "GetRange"); err != nil {
+		return nil, err
+	}
+
+	if timeout <= 0 {
+		timeout = r.wTimeout
+	}
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = r.clock.Now().Add(timeout)
+	}
+
+	if minN > 1 {
+		r.bulkReaders++
+		defer func() {
+			r.bulkReaders--
+		}()
+	}
+
+	timedOut := false
+	for r.Length(true) < minN && r.err != io.EOF {
+		if !r.block {
+			return nil, errors.ErrIsEmpty
+		}
+
+		if err := r.waitWriteUntil(deadline, "GetRange"); err != nil {
+			if !stderrors.Is(err, errors.ErrReadTimeout) {
+				return nil, err
+			}
+			timedOut = true
+			break
+		}
+
+		if err := r.readErr(true, "GetRange"); err != nil {
+			return nil, err
+		}
+	}
+
+	n := min(r.Length(true), maxN)
+	if n == 0 {
+		if timedOut {
+			return nil, errors.ErrReadTimeout
+		}
+		return nil, io.EOF
+	}
+
+	items = make([]T, n)
+	r.copyOut(items)
+	r.consume(n)
+
+	r.afterRead(n)
+
+	if timedOut && !r.quietRangeTimeout {
+		return items, errors.ErrReadTimeout
+	}
+
+	return items, nil
+}